@@ -5,58 +5,348 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"xhark/internal/ui"
 )
 
+// timeoutMethods get their own --timeout-<method> flag.
+var timeoutMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
 func main() {
 	var (
-		baseURL  string
-		specURL  string
-		specFile string
+		baseURL   string
+		specs     []string
+		override  string
+		specPaths string
+		refresh   bool
+		postmanF  string
+		maxBodyKB int
+		jsonDepth int
+		jsonItems int
+		noEditor  bool
+		jsonc     bool
+		logPretty bool
+		merge     bool
+		timeout   time.Duration
+		h2c       bool
+		caCert    string
+		certFile  string
+		keyFile   string
+		bodyEnv   string
+		accept    string
+		noAuth    bool
+		checkSrv  bool
+		cache     bool
+		noRedir   bool
+		insecure  bool
+		proxy     string
+		headers   [][2]string
+		warnSlow  time.Duration
 	)
 
 	flag.StringVar(&baseURL, "base-url", "", "Base URL for executing requests (e.g. http://localhost:8000)")
-	flag.StringVar(&specURL, "spec-url", "", "OpenAPI spec URL (http/https)")
-	flag.StringVar(&specFile, "spec-file", "", "Path to local OpenAPI spec file")
+	// Both may be repeated; specs are listed in the order given.
+	flag.Func("spec-url", "OpenAPI spec URL (http/https); repeat to load several specs", func(v string) error {
+		if v = strings.TrimSpace(v); v != "" {
+			specs = append(specs, v)
+		}
+		return nil
+	})
+	flag.Func("spec-file", "Path to local OpenAPI spec file; repeat to load several specs", func(v string) error {
+		if v = strings.TrimSpace(v); v != "" {
+			specs = append(specs, specFileArg(v))
+		}
+		return nil
+	})
+	flag.BoolVar(&refresh, "refresh", false, "Download specs anew instead of using the copies cached on disk")
+	flag.StringVar(&postmanF, "postman-file", "", "Path to a Postman v2.1 collection to use instead of an OpenAPI spec")
+	flag.StringVar(&override, "spec-override", "", "Path to a JSON or YAML file deep-merged over the spec at load time")
+	flag.StringVar(&specPaths, "spec-paths", "", "Comma-separated paths probed under --base-url when no spec is given (default /openapi.json,/swagger.json,...)")
+	flag.IntVar(&maxBodyKB, "max-body-kb", 0, "Response body display limit in KB before truncating (default 512)")
+	flag.IntVar(&jsonDepth, "json-depth", 0, "Collapse JSON response nodes nested deeper than this (0 = unlimited)")
+	flag.IntVar(&jsonItems, "json-max-items", 0, "Show only the first N elements of JSON arrays (0 = unlimited)")
+	flag.BoolVar(&noEditor, "no-editor", false, "Edit request bodies field by field instead of in $EDITOR")
+	flag.BoolVar(&jsonc, "jsonc", false, "Allow comments and trailing commas in edited request bodies")
+	flag.DurationVar(&timeout, "timeout", 0, "Request timeout (default 20s)")
+	methodTimeouts := map[string]*time.Duration{}
+	for _, m := range timeoutMethods {
+		methodTimeouts[m] = flag.Duration("timeout-"+strings.ToLower(m), 0, "Request timeout for "+m+" requests (overrides --timeout)")
+	}
+	flag.DurationVar(&warnSlow, "warn-slow", 0, "Highlight response times above this duration (e.g. 500ms)")
+	flag.StringVar(&bodyEnv, "body-env", "", "Name of an environment variable holding a JSON request body to pre-fill")
+	flag.Func("header", `Default header for every request, as "Name: value"; repeat for several`, func(v string) error {
+		name, value, err := parseHeader(v)
+		if err != nil {
+			return err
+		}
+		headers = append(headers, [2]string{name, value})
+		return nil
+	})
+	flag.StringVar(&accept, "accept", "", "Default Accept header for every request (e.g. application/json)")
+	flag.BoolVar(&noAuth, "no-auth", false, "Send requests without credentials, even to endpoints that require them")
+	flag.BoolVar(&checkSrv, "check-server", false, "Warn after startup if a spec's base URL can't be reached")
+	flag.BoolVar(&cache, "cache", false, "Show the last response to an identical request from this session instead of sending it again")
+	flag.BoolVar(&noRedir, "no-redirects", false, "Show 3xx responses and their Location instead of following redirects")
+	flag.BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification for specs and requests (self-signed staging servers)")
+	flag.StringVar(&proxy, "proxy", "", "Proxy URL for specs, requests and token fetches (default: HTTP_PROXY/HTTPS_PROXY)")
+	flag.BoolVar(&h2c, "h2c", false, "Use HTTP/2 without TLS (h2c, prior knowledge) for http:// URLs")
+	flag.StringVar(&caCert, "cacert", "", "PEM file of CA certificates to trust for specs and requests, besides the system roots")
+	flag.StringVar(&certFile, "client-cert", "", "PEM client certificate for mutual TLS (use with --client-key)")
+	flag.StringVar(&keyFile, "client-key", "", "PEM private key for --client-cert")
+	flag.BoolVar(&merge, "merge-specs", false, "List the endpoints of all given specs in one list instead of switching between them")
+	flag.BoolVar(&logPretty, "log-pretty", false, "Indent JSON bodies in the debug log instead of logging them raw")
 	flag.Parse()
 
 	// CLI args take precedence over env.
-	spec := strings.TrimSpace(specURL)
-	if spec == "" {
-		spec = strings.TrimSpace(specFile)
-		if spec != "" {
-			if abs, err := filepath.Abs(spec); err == nil {
-				spec = abs
-			}
-			// Use explicit marker for local file.
-			spec = "@" + spec
-		}
-	}
-	if spec == "" {
+	if len(specs) == 0 {
 		// Env fallback.
 		if envSpecFile := strings.TrimSpace(os.Getenv("XHARK_SPEC_FILE")); envSpecFile != "" {
-			if abs, err := filepath.Abs(envSpecFile); err == nil {
-				envSpecFile = abs
-			}
-			spec = "@" + envSpecFile
-		} else {
-			spec = strings.TrimSpace(os.Getenv("XHARK_SPEC_URL"))
+			specs = append(specs, specFileArg(envSpecFile))
+		} else if envSpecURL := strings.TrimSpace(os.Getenv("XHARK_SPEC_URL")); envSpecURL != "" {
+			specs = append(specs, envSpecURL)
 		}
 	}
 
 	if baseURL == "" {
 		baseURL = strings.TrimSpace(os.Getenv("XHARK_BASE_URL"))
 	}
+	if override == "" {
+		override = strings.TrimSpace(os.Getenv("XHARK_SPEC_OVERRIDE"))
+	}
+	if specPaths == "" {
+		specPaths = strings.TrimSpace(os.Getenv("XHARK_SPEC_PATHS"))
+	}
+	if !refresh {
+		refresh, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_REFRESH")))
+	}
+	if postmanF == "" {
+		postmanF = strings.TrimSpace(os.Getenv("XHARK_POSTMAN_FILE"))
+	}
+	if postmanF != "" {
+		if abs, err := filepath.Abs(postmanF); err == nil {
+			postmanF = abs
+		}
+	}
+
+	if maxBodyKB == 0 {
+		if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("XHARK_MAX_BODY_KB"))); err == nil {
+			maxBodyKB = n
+		}
+	}
+
+	if jsonDepth == 0 {
+		if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("XHARK_JSON_DEPTH"))); err == nil {
+			jsonDepth = n
+		}
+	}
+
+	if jsonItems == 0 {
+		if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("XHARK_JSON_MAX_ITEMS"))); err == nil {
+			jsonItems = n
+		}
+	}
+
+	if !noEditor {
+		noEditor, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_NO_EDITOR")))
+	}
+
+	if !jsonc {
+		jsonc, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_JSONC")))
+	}
+
+	if timeout == 0 {
+		timeout, _ = time.ParseDuration(strings.TrimSpace(os.Getenv("XHARK_TIMEOUT")))
+	}
+	if warnSlow == 0 {
+		warnSlow, _ = time.ParseDuration(strings.TrimSpace(os.Getenv("XHARK_WARN_SLOW")))
+	}
+	for _, m := range timeoutMethods {
+		if *methodTimeouts[m] == 0 {
+			*methodTimeouts[m], _ = time.ParseDuration(strings.TrimSpace(os.Getenv("XHARK_TIMEOUT_" + m)))
+		}
+	}
+
+	if len(headers) == 0 {
+		// one per line: values like cookies contain ";" and ","
+		for _, h := range strings.Split(os.Getenv("XHARK_HEADERS"), "\n") {
+			if strings.TrimSpace(h) == "" {
+				continue
+			}
+			name, value, err := parseHeader(h)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "XHARK_HEADERS: %v\n", err)
+				os.Exit(2)
+			}
+			headers = append(headers, [2]string{name, value})
+		}
+	}
+
+	if accept == "" {
+		accept = strings.TrimSpace(os.Getenv("XHARK_ACCEPT"))
+	}
+
+	if !noAuth {
+		noAuth, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_NO_AUTH")))
+	}
+
+	if !checkSrv {
+		checkSrv, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_CHECK_SERVER")))
+	}
+
+	if !cache {
+		cache, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_CACHE")))
+	}
+
+	if !noRedir {
+		noRedir, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_NO_REDIRECTS")))
+	}
+
+	if !insecure {
+		insecure, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_INSECURE")))
+	}
+
+	if proxy == "" {
+		proxy = strings.TrimSpace(os.Getenv("XHARK_PROXY"))
+	}
+
+	if !h2c {
+		h2c, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_H2C")))
+	}
+
+	if caCert == "" {
+		caCert = strings.TrimSpace(os.Getenv("XHARK_CACERT"))
+	}
+
+	if certFile == "" {
+		certFile = strings.TrimSpace(os.Getenv("XHARK_CLIENT_CERT"))
+	}
+	if keyFile == "" {
+		keyFile = strings.TrimSpace(os.Getenv("XHARK_CLIENT_KEY"))
+	}
+
+	if !merge {
+		merge, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_MERGE_SPECS")))
+	}
+
+	if !logPretty {
+		logPretty, _ = strconv.ParseBool(strings.TrimSpace(os.Getenv("XHARK_LOG_PRETTY")))
+	}
 
 	app := ui.NewApp(os.Stdin, os.Stdout)
-	if spec != "" {
-		app.SetSpec(spec)
+	for i, spec := range specs {
+		if i == 0 {
+			app.SetSpec(spec)
+		} else {
+			app.AddSpec(spec)
+		}
+	}
+	if override != "" {
+		app.SetSpecOverride(override)
+	}
+	if specPaths != "" {
+		app.SetSpecPaths(strings.Split(specPaths, ","))
+	}
+	if refresh {
+		app.SetRefresh(true)
+	}
+	if postmanF != "" {
+		app.SetPostmanFile(postmanF)
 	}
 	if baseURL != "" {
 		app.SetBaseURL(baseURL)
 	}
+	if maxBodyKB > 0 {
+		app.SetMaxBodyKB(maxBodyKB)
+	}
+	if jsonDepth > 0 {
+		app.SetJSONDepth(jsonDepth)
+	}
+	if jsonItems > 0 {
+		app.SetJSONMaxItems(jsonItems)
+	}
+	if noEditor {
+		app.SetNoEditor(true)
+	}
+	if jsonc {
+		app.SetJSONC(true)
+	}
+	if logPretty {
+		app.SetLogPretty(true)
+	}
+	if merge {
+		app.SetMergeSpecs(true)
+	}
+	if h2c {
+		app.SetH2C(true)
+	}
+	if caCert != "" {
+		if err := app.SetCACert(caCert); err != nil {
+			fmt.Fprintf(os.Stderr, "--cacert: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			fmt.Fprintln(os.Stderr, "--client-cert and --client-key must be given together")
+			os.Exit(2)
+		}
+		if err := app.SetClientCert(certFile, keyFile); err != nil {
+			fmt.Fprintf(os.Stderr, "--client-cert: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	for _, h := range headers {
+		app.SetHeader(h[0], h[1])
+	}
+	if accept != "" {
+		app.SetAccept(accept)
+	}
+	if noAuth {
+		app.SetNoAuth(true)
+	}
+	if checkSrv {
+		app.SetCheckServer(true)
+	}
+	if cache {
+		app.SetCacheResponses(true)
+	}
+	if noRedir {
+		app.SetNoRedirects(true)
+	}
+	if insecure {
+		app.SetInsecure(true)
+	}
+	if proxy != "" {
+		if err := app.SetProxy(proxy); err != nil {
+			fmt.Fprintf(os.Stderr, "--proxy: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	if warnSlow > 0 {
+		app.SetWarnSlow(warnSlow)
+	}
+	if timeout > 0 {
+		app.SetTimeout(timeout)
+	}
+	for _, m := range timeoutMethods {
+		if d := *methodTimeouts[m]; d > 0 {
+			app.SetMethodTimeout(m, d)
+		}
+	}
+	if bodyEnv = strings.TrimSpace(bodyEnv); bodyEnv != "" {
+		body, ok := os.LookupEnv(bodyEnv)
+		if !ok || strings.TrimSpace(body) == "" {
+			fmt.Fprintf(os.Stderr, "--body-env: %s is not set\n", bodyEnv)
+			os.Exit(2)
+		}
+		if err := app.SetInitialBody(body); err != nil {
+			fmt.Fprintf(os.Stderr, "--body-env: %s: %v\n", bodyEnv, err)
+			os.Exit(2)
+		}
+	}
 	if err := app.Init(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
@@ -66,3 +356,22 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// specFileArg turns a spec file path into the "@/abs/path" form the app
+// uses to tell local files from URLs.
+func specFileArg(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+	return "@" + p
+}
+
+// parseHeader splits a "Name: value" header argument.
+func parseHeader(v string) (string, string, error) {
+	name, value, ok := strings.Cut(v, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("%q: want \"Name: value\"", v)
+	}
+	return name, strings.TrimSpace(value), nil
+}
@@ -4,13 +4,13 @@ go 1.25
 
 require (
 	github.com/getkin/kin-openapi v0.128.0
+	github.com/invopop/yaml v0.3.1
 	github.com/jroimartin/gocui v0.5.0
 )
 
 require (
 	github.com/go-openapi/jsonpointer v0.21.0 // indirect
 	github.com/go-openapi/swag v0.23.0 // indirect
-	github.com/invopop/yaml v0.3.1 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/mattn/go-runewidth v0.0.9 // indirect
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AuthToken is a credential acquired for one security scheme.
+type AuthToken struct {
+	Token      string    `json:"token"`
+	TokenType  string    `json:"tokenType,omitempty"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+}
+
+// AuthStore persists acquired tokens across restarts, keyed by spec, then
+// auth profile, then security scheme name. It's kept apart from Config so
+// secrets never end up in a file people share.
+type AuthStore struct {
+	Specs map[string]map[string]map[string]AuthToken `json:"specs,omitempty"`
+
+	path string
+}
+
+// AuthPath returns the token store location: <user config dir>/xhark/auth.json,
+// or the path in $XHARK_AUTH_STORE. It returns "" when XHARK_AUTH_STORE is
+// "0", "false" or "off", which turns persistence off.
+func AuthPath() (string, error) {
+	env := strings.TrimSpace(os.Getenv("XHARK_AUTH_STORE"))
+	switch strings.ToLower(env) {
+	case "0", "false", "off":
+		return "", nil
+	case "", "1", "true", "on":
+	default:
+		return env, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "xhark", "auth.json"), nil
+}
+
+// LoadAuth reads the token store. It returns nil when persistence is off; a
+// missing file yields an empty store.
+func LoadAuth() (*AuthStore, error) {
+	p, err := AuthPath()
+	if err != nil || p == "" {
+		return nil, err
+	}
+	s := &AuthStore{path: p}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, fmt.Errorf("read auth store %s: %w", p, err)
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return s, fmt.Errorf("parse auth store %s: %w", p, err)
+	}
+	return s, nil
+}
+
+// Save writes the store back, readable by the user only.
+func (s *AuthStore) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(b, '\n'), 0o600)
+}
+
+// Spec returns the tokens stored for a spec, by profile then scheme.
+func (s *AuthStore) Spec(key string) map[string]map[string]AuthToken {
+	return s.Specs[key]
+}
+
+// SetSpec replaces the tokens stored for a spec; empty profiles are dropped.
+func (s *AuthStore) SetSpec(key string, profiles map[string]map[string]AuthToken) {
+	for name, tokens := range profiles {
+		if len(tokens) == 0 {
+			delete(profiles, name)
+		}
+	}
+	if len(profiles) == 0 {
+		delete(s.Specs, key)
+		return
+	}
+	if s.Specs == nil {
+		s.Specs = map[string]map[string]map[string]AuthToken{}
+	}
+	s.Specs[key] = profiles
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SpecConfig holds per-spec user state.
+type SpecConfig struct {
+	// Pinned are endpoint keys ("METHOD /path") shown at the top of the list.
+	Pinned []string `json:"pinned,omitempty"`
+	// Presets are named, saved requests keyed by operation (operationId, or
+	// "METHOD /path" when the spec has none).
+	Presets map[string][]Preset `json:"presets,omitempty"`
+	// AuthProfiles names the credential sets kept besides the default one;
+	// AuthProfile is the one last in use. Tokens themselves aren't stored.
+	AuthProfiles []string `json:"authProfiles,omitempty"`
+	AuthProfile  string   `json:"authProfile,omitempty"`
+	// Notes are free-form notes keyed by endpoint key ("METHOD /path").
+	Notes map[string]string `json:"notes,omitempty"`
+}
+
+// Preset is a saved request configuration for one operation.
+type Preset struct {
+	Name       string            `json:"name"`
+	PathVals   map[string]string `json:"path,omitempty"`
+	QueryVals  map[string]string `json:"query,omitempty"`
+	HeaderVals map[string]string `json:"headers,omitempty"`
+	BodyVals   map[string]string `json:"bodyFields,omitempty"`
+	BodyRaw    string            `json:"body,omitempty"`
+	// Auth names the security scheme(s) the request was authorized with.
+	Auth string `json:"auth,omitempty"`
+}
+
+// Config is the persisted user configuration, stored as JSON.
+type Config struct {
+	Specs map[string]*SpecConfig `json:"specs,omitempty"`
+
+	path string
+}
+
+// Path returns the config file location: $XHARK_CONFIG, otherwise
+// <user config dir>/xhark/config.json.
+func Path() (string, error) {
+	if p := strings.TrimSpace(os.Getenv("XHARK_CONFIG")); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "xhark", "config.json"), nil
+}
+
+// Load reads the config file. A missing file yields an empty config.
+func Load() (*Config, error) {
+	p, err := Path()
+	if err != nil {
+		return &Config{}, err
+	}
+	c := &Config{path: p}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return c, fmt.Errorf("read config %s: %w", p, err)
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return c, fmt.Errorf("parse config %s: %w", p, err)
+	}
+	return c, nil
+}
+
+// Save writes the config back to the file it was loaded from.
+func (c *Config) Save() error {
+	if c.path == "" {
+		p, err := Path()
+		if err != nil {
+			return err
+		}
+		c.path = p
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(b, '\n'), 0o600)
+}
+
+// Spec returns the state for a spec, creating it if needed.
+func (c *Config) Spec(key string) *SpecConfig {
+	if c.Specs == nil {
+		c.Specs = map[string]*SpecConfig{}
+	}
+	sc, ok := c.Specs[key]
+	if !ok {
+		sc = &SpecConfig{}
+		c.Specs[key] = sc
+	}
+	return sc
+}
+
+// IsPinned reports whether the endpoint key is pinned.
+func (s *SpecConfig) IsPinned(key string) bool {
+	for _, p := range s.Pinned {
+		if p == key {
+			return true
+		}
+	}
+	return false
+}
+
+// TogglePin pins or unpins the endpoint key and reports the new state.
+func (s *SpecConfig) TogglePin(key string) bool {
+	for i, p := range s.Pinned {
+		if p == key {
+			s.Pinned = append(s.Pinned[:i], s.Pinned[i+1:]...)
+			return false
+		}
+	}
+	s.Pinned = append(s.Pinned, key)
+	return true
+}
+
+// SetNote stores the note for an endpoint key; an empty note removes it.
+func (s *SpecConfig) SetNote(key, note string) {
+	if note == "" {
+		delete(s.Notes, key)
+		return
+	}
+	if s.Notes == nil {
+		s.Notes = map[string]string{}
+	}
+	s.Notes[key] = note
+}
+
+// SavePreset stores p for the operation, replacing a preset of the same name.
+func (s *SpecConfig) SavePreset(op string, p Preset) {
+	if s.Presets == nil {
+		s.Presets = map[string][]Preset{}
+	}
+	list := s.Presets[op]
+	for i := range list {
+		if list[i].Name == p.Name {
+			list[i] = p
+			return
+		}
+	}
+	s.Presets[op] = append(list, p)
+}
+
+// DeletePreset removes the named preset for the operation.
+func (s *SpecConfig) DeletePreset(op, name string) {
+	list := s.Presets[op]
+	for i := range list {
+		if list[i].Name == name {
+			s.Presets[op] = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(s.Presets[op]) == 0 {
+		delete(s.Presets, op)
+	}
+}
@@ -0,0 +1,348 @@
+package curl
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Command is the request a curl command line describes.
+type Command struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+// ErrUnterminatedQuote is returned for a command line that ends inside a
+// quoted word.
+var ErrUnterminatedQuote = errors.New("curl: unterminated quote")
+
+// flags that take a value; anything else starting with "-" is a switch.
+var valueFlags = map[string]string{
+	"-X":               "request",
+	"--request":        "request",
+	"-H":               "header",
+	"--header":         "header",
+	"-d":               "data",
+	"--data":           "data",
+	"--data-raw":       "data",
+	"--data-binary":    "data",
+	"--data-ascii":     "data",
+	"--data-urlencode": "data-urlencode",
+	"--json":           "json",
+	"-u":               "user",
+	"--user":           "user",
+	"-A":               "user-agent",
+	"--user-agent":     "user-agent",
+	"-e":               "referer",
+	"--referer":        "referer",
+	"-b":               "cookie",
+	"--cookie":         "cookie",
+	"--url":            "url",
+	"-F":               "form",
+	"--form":           "form",
+
+	// accepted and ignored
+	"-o":                "",
+	"--output":          "",
+	"-m":                "",
+	"--max-time":        "",
+	"--connect-timeout": "",
+	"--retry":           "",
+	"-w":                "",
+	"--write-out":       "",
+	"-x":                "",
+	"--proxy":           "",
+	"--cacert":          "",
+	"--cert":            "",
+	"-E":                "",
+	"--key":             "",
+	"-c":                "",
+	"--cookie-jar":      "",
+	"--resolve":         "",
+	"--limit-rate":      "",
+	"-r":                "",
+	"--range":           "",
+}
+
+// Parse reads a curl command as copied from browser devtools or a terminal:
+// -X, -H, the -d/--data family, --json, -u, -b, -A, -e, -G and -I are
+// understood; common switches (--compressed, -k, -s, -L, ...) are ignored.
+// Multipart forms (-F) aren't supported.
+func Parse(cmdline string) (Command, error) {
+	args, err := split(cmdline)
+	if err != nil {
+		return Command{}, err
+	}
+	if len(args) > 0 && (args[0] == "curl" || strings.HasSuffix(args[0], "/curl")) {
+		args = args[1:]
+	}
+
+	cmd := Command{Headers: map[string]string{}}
+	var data []string
+	get, head := false, false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if cmd.URL == "" {
+				cmd.URL = arg
+			}
+			continue
+		}
+		name, val, hasVal := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			if n, v, ok := strings.Cut(arg, "="); ok {
+				if _, known := valueFlags[n]; known {
+					name, val, hasVal = n, v, true
+				}
+			}
+		} else if len(arg) > 2 {
+			// -XPOST, -sSL, -sH 'x: y'
+			for j := 1; j < len(arg); j++ {
+				short := "-" + arg[j:j+1]
+				if _, takes := valueFlags[short]; takes {
+					name = short
+					if j+1 < len(arg) {
+						val, hasVal = arg[j+1:], true
+					}
+					break
+				}
+				if short == "-G" {
+					get = true
+				}
+				if short == "-I" {
+					head = true
+				}
+				name = ""
+			}
+			if name == "" {
+				continue
+			}
+		}
+
+		kind, takes := valueFlags[name]
+		if !takes {
+			switch name {
+			case "-G", "--get":
+				get = true
+			case "-I", "--head":
+				head = true
+			}
+			continue
+		}
+		if !hasVal {
+			if i+1 >= len(args) {
+				return Command{}, fmt.Errorf("curl: %s needs a value", name)
+			}
+			i++
+			val = args[i]
+		}
+		switch kind {
+		case "request":
+			cmd.Method = strings.ToUpper(val)
+		case "header":
+			k, v, _ := strings.Cut(val, ":")
+			if k = strings.TrimSpace(k); k != "" {
+				cmd.Headers[k] = strings.TrimSpace(v)
+			}
+		case "data":
+			data = append(data, val)
+		case "data-urlencode":
+			if k, v, ok := strings.Cut(val, "="); ok {
+				data = append(data, url.QueryEscape(k)+"="+url.QueryEscape(v))
+			} else {
+				data = append(data, url.QueryEscape(val))
+			}
+		case "json":
+			data = append(data, val)
+			setDefault(cmd.Headers, "Content-Type", "application/json")
+			setDefault(cmd.Headers, "Accept", "application/json")
+		case "user":
+			cmd.Headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(val))
+		case "user-agent":
+			cmd.Headers["User-Agent"] = val
+		case "referer":
+			cmd.Headers["Referer"] = val
+		case "cookie":
+			cmd.Headers["Cookie"] = val
+		case "url":
+			cmd.URL = val
+		case "form":
+			return Command{}, fmt.Errorf("curl: multipart forms (-F) aren't supported")
+		}
+	}
+	if cmd.URL == "" {
+		return Command{}, fmt.Errorf("curl: no URL")
+	}
+	if !strings.Contains(cmd.URL, "://") {
+		cmd.URL = "http://" + cmd.URL
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case get && body != "":
+		sep := "?"
+		if strings.Contains(cmd.URL, "?") {
+			sep = "&"
+		}
+		cmd.URL += sep + body
+		body = ""
+	case body != "":
+		cmd.Body = body
+		setDefault(cmd.Headers, "Content-Type", "application/x-www-form-urlencoded")
+	}
+	if cmd.Method == "" {
+		switch {
+		case head:
+			cmd.Method = "HEAD"
+		case cmd.Body != "":
+			cmd.Method = "POST"
+		default:
+			cmd.Method = "GET"
+		}
+	}
+	return cmd, nil
+}
+
+// Incomplete reports whether cmdline goes on past its last line: it ends in
+// a line continuation or inside a quote, as a multi-line command pasted one
+// line at a time does until its last line.
+func Incomplete(cmdline string) bool {
+	trimmed := strings.TrimRight(cmdline, " \t\r\n")
+	if n := len(trimmed) - len(strings.TrimRight(trimmed, `\`)); n%2 == 1 {
+		return true
+	}
+	_, err := split(cmdline)
+	return errors.Is(err, ErrUnterminatedQuote)
+}
+
+func setDefault(h map[string]string, key, val string) {
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			return
+		}
+	}
+	h[key] = val
+}
+
+// split breaks a shell command line into words, handling '...', "...",
+// $'...' (as copied by Chrome), backslash escapes and line continuations.
+func split(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			if s[i] == '\n' || s[i] == '\r' {
+				// line continuation
+				if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+					i++
+				}
+				continue
+			}
+			cur.WriteByte(s[i])
+			inWord = true
+		case c == '\'':
+			j := strings.IndexByte(s[i+1:], '\'')
+			if j < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+			cur.WriteString(s[i+1 : i+1+j])
+			i += j + 1
+			inWord = true
+		case c == '$' && i+1 < len(s) && s[i+1] == '\'':
+			i += 2
+			for ; i < len(s) && s[i] != '\''; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					cur.WriteString(ansiCEscape(s[i]))
+					continue
+				}
+				cur.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, ErrUnterminatedQuote
+			}
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				cur.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, ErrUnterminatedQuote
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+func ansiCEscape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 't':
+		return "\t"
+	case 'r':
+		return "\r"
+	default:
+		return string(c)
+	}
+}
+
+// String renders c as a curl command line, one option per line, with every
+// value single-quoted so tokens and JSON bodies survive a paste into a shell.
+// Parse reads it back.
+func (c Command) String() string {
+	var b strings.Builder
+	b.WriteString("curl")
+	method := strings.ToUpper(c.Method)
+	if method != "" && (method != "GET" || c.Body != "") {
+		b.WriteString(" -X " + method)
+	}
+	b.WriteString(" " + shellQuote(c.URL))
+
+	names := make([]string, 0, len(c.Headers))
+	for k := range c.Headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		b.WriteString(" \\\n  -H " + shellQuote(k+": "+c.Headers[k]))
+	}
+	if c.Body != "" {
+		b.WriteString(" \\\n  --data-raw " + shellQuote(c.Body))
+	}
+	return b.String()
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,54 @@
+package curl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringParsesBack(t *testing.T) {
+	c := Command{
+		Method:  "POST",
+		URL:     "https://api.example.com/pets?tag=a%20b",
+		Headers: map[string]string{"Authorization": "Bearer t0k'en", "Content-Type": "application/json", "X-Trace": "1"},
+		Body:    "{\n  \"name\": \"it's\"\n}",
+	}
+	got, err := Parse(c.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("Parse(String()) = %+v, want %+v", got, c)
+	}
+}
+
+func TestIncomplete(t *testing.T) {
+	c := Command{
+		Method:  "POST",
+		URL:     "https://api.example.com/pets",
+		Headers: map[string]string{"X-Trace": "1"},
+		Body:    "{\n  \"name\": \"rex\"\n}",
+	}
+	// fed a line at a time, as a paste arrives, only the last line completes it
+	lines := strings.Split(c.String(), "\n")
+	var cmdline string
+	for i, line := range lines {
+		cmdline += line
+		last := i == len(lines)-1
+		if Incomplete(cmdline) == last {
+			t.Fatalf("Incomplete after line %d (%q) = %v", i+1, line, !last)
+		}
+		cmdline += "\n"
+	}
+
+	for _, s := range []string{`curl https://h/x`, `curl https://h/x -H 'a: \\'`, `curl 'https://h/x\'`} {
+		if Incomplete(s) {
+			t.Errorf("Incomplete(%q) = true", s)
+		}
+	}
+	for _, s := range []string{`curl https://h/x \`, `curl https://h/x -d '{`, `curl "https://h/x`} {
+		if !Incomplete(s) {
+			t.Errorf("Incomplete(%q) = false", s)
+		}
+	}
+}
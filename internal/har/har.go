@@ -0,0 +1,178 @@
+package har
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"xhark/internal/httpclient"
+)
+
+// Entry is one executed request and the response it got.
+type Entry struct {
+	Request  httpclient.RequestSpec
+	Response httpclient.Result
+}
+
+// The types below are the parts of the HAR 1.2 format we fill in.
+
+type Log struct {
+	Log struct {
+		Version string     `json:"version"`
+		Creator Creator    `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         timings     `json:"timings"`
+}
+
+type nameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []nameValue `json:"cookies"`
+	Headers     []nameValue `json:"headers"`
+	QueryString []nameValue `json:"queryString"`
+	PostData    *postData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type postData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []nameValue `json:"cookies"`
+	Headers     []nameValue `json:"headers"`
+	Content     content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// timings only knows the total; phases we didn't measure are -1 per spec.
+type timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Build serializes entries as a HAR 1.2 document.
+func Build(entries []Entry) ([]byte, error) {
+	var l Log
+	l.Log.Version = "1.2"
+	l.Log.Creator = Creator{Name: "xhark", Version: "dev"}
+	l.Log.Entries = []harEntry{}
+	for _, e := range entries {
+		l.Log.Entries = append(l.Log.Entries, buildEntry(e))
+	}
+	return json.MarshalIndent(l, "", "  ")
+}
+
+func buildEntry(e Entry) harEntry {
+	req, res := e.Request, e.Response
+	ms := float64(res.Elapsed) / float64(time.Millisecond)
+	proto := res.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	hr := harRequest{
+		Method:      req.Method,
+		URL:         req.URL,
+		HTTPVersion: proto,
+		Cookies:     []nameValue{},
+		Headers:     sortedPairs(req.Headers),
+		QueryString: []nameValue{},
+		HeadersSize: -1,
+		BodySize:    len(req.Body),
+	}
+	if u, err := url.Parse(req.URL); err == nil {
+		for k, vs := range u.Query() {
+			for _, v := range vs {
+				hr.QueryString = append(hr.QueryString, nameValue{Name: k, Value: v})
+			}
+		}
+		sort.Slice(hr.QueryString, func(i, j int) bool { return hr.QueryString[i].Name < hr.QueryString[j].Name })
+	}
+	if len(req.Body) > 0 {
+		hr.PostData = &postData{MimeType: headerValue(req.Headers, "Content-Type"), Text: string(req.Body)}
+	}
+
+	// Status is "200 OK"; HAR wants the reason phrase on its own.
+	statusText := res.Status
+	if _, after, ok := strings.Cut(res.Status, " "); ok {
+		statusText = after
+	}
+	return harEntry{
+		StartedDateTime: res.Started.Format(time.RFC3339Nano),
+		Time:            ms,
+		Request:         hr,
+		Response: harResponse{
+			Status:      res.StatusCode,
+			StatusText:  statusText,
+			HTTPVersion: proto,
+			Cookies:     []nameValue{},
+			Headers:     sortedPairs(res.Headers),
+			Content: content{
+				Size:     len(res.Raw),
+				MimeType: res.Headers["content-type"],
+				Text:     string(res.Raw),
+			},
+			HeadersSize: -1,
+			BodySize:    len(res.Raw),
+		},
+		Timings: timings{Blocked: -1, DNS: -1, Connect: -1, Send: 0, Wait: ms, Receive: 0},
+	}
+}
+
+func sortedPairs(m map[string]string) []nameValue {
+	out := []nameValue{}
+	for k, v := range m {
+		out = append(out, nameValue{Name: k, Value: v})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func headerValue(h map[string]string, name string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
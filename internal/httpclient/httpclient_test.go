@@ -0,0 +1,389 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"xhark/internal/model"
+)
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestUndeclaredPathPlaceholder(t *testing.T) {
+	ep := model.Endpoint{
+		Method:     "GET",
+		Path:       "/orgs/{org}/repos/{foo}/issues/{number}",
+		PathParams: []model.Param{{Name: "org", Required: true}, {Name: "number", Required: true}},
+	}
+	_, err := BuildRequest(nil, "https://h", ep, map[string]string{"org": "acme", "number": "3"}, nil, nil, nil, "")
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want a ValidationError", err)
+	}
+	if verr.Pane != "path" || verr.Reason != "unresolved path parameter: foo" {
+		t.Errorf("err = %+v, want path: unresolved path parameter: foo", verr)
+	}
+
+	// A value that looks like a placeholder is escaped, not mistaken for one.
+	ep.Path = "/orgs/{org}/issues/{number}"
+	req, err := BuildRequest(nil, "https://h", ep, map[string]string{"org": "{x}", "number": "3"}, nil, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL != "https://h/orgs/%7Bx%7D/issues/3" {
+		t.Errorf("URL = %s", req.URL)
+	}
+}
+
+func TestAllowEmptyValueQuery(t *testing.T) {
+	ep := model.Endpoint{Method: "GET", Path: "/items", QueryParams: []model.Param{
+		{Name: "filter", Type: model.TypeString, AllowEmptyValue: true},
+		{Name: "archived", Type: model.TypeBoolean, AllowEmptyValue: true},
+		{Name: "q", Type: model.TypeString},
+	}}
+	tests := []struct {
+		vals map[string]string
+		want string
+	}{
+		{map[string]string{}, "https://h/items"},
+		{map[string]string{"filter": ""}, "https://h/items?filter="},
+		{map[string]string{"filter": "  "}, "https://h/items?filter="},
+		{map[string]string{"archived": ""}, "https://h/items?archived"},
+		{map[string]string{"archived": "true"}, "https://h/items?archived"},
+		{map[string]string{"archived": "false"}, "https://h/items"},
+		{map[string]string{"q": ""}, "https://h/items"},
+		{map[string]string{"filter": "", "archived": "", "q": "x"}, "https://h/items?filter=&q=x&archived"},
+	}
+	for _, tt := range tests {
+		req, err := BuildRequest(nil, "https://h", ep, nil, tt.vals, nil, nil, "")
+		if err != nil {
+			t.Errorf("%v: %v", tt.vals, err)
+			continue
+		}
+		if req.URL != tt.want {
+			t.Errorf("%v: URL = %s, want %s", tt.vals, req.URL, tt.want)
+		}
+	}
+}
+
+func TestBaseURLCredentialsBecomeBasicAuth(t *testing.T) {
+	var gotAuth, gotURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotURL = r.Header.Get("Authorization"), r.URL.String()
+	}))
+	defer srv.Close()
+
+	base := strings.Replace(srv.URL, "http://", "http://user:p%40ss@", 1) + "/api"
+	req, err := BuildRequest(nil, base, model.Endpoint{Method: "GET", Path: "/me"}, nil, nil, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL != srv.URL+"/api/me" {
+		t.Errorf("URL = %s, want the credentials stripped", req.URL)
+	}
+	want := "Basic dXNlcjpwQHNz" // user:p@ss
+	if req.Headers["Authorization"] != want {
+		t.Errorf("Authorization = %q, want %q", req.Headers["Authorization"], want)
+	}
+
+	if _, err := Execute(context.Background(), nil, req); err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != want || gotURL != "/api/me" {
+		t.Errorf("server got Authorization %q for %s", gotAuth, gotURL)
+	}
+}
+
+func TestBuildRequestJoinsBaseAndPath(t *testing.T) {
+	tests := []struct {
+		base, path, want string
+	}{
+		{"https://host", "/users", "https://host/users"},
+		{"https://host/", "/users", "https://host/users"},
+		{"https://host", "users", "https://host/users"},
+		{"https://host/api/v1", "/users", "https://host/api/v1/users"},
+		{"https://host/api/v1/", "/users", "https://host/api/v1/users"},
+		{"https://host/api/v1//", "users", "https://host/api/v1/users"},
+		{"https://host/api", "/", "https://host/api/"},
+		{"https://host/api", "/users/", "https://host/api/users/"},
+		{"https://host:8443/api?tenant=a", "/users", "https://host:8443/api/users?tenant=a"},
+	}
+	for _, tt := range tests {
+		req, err := BuildRequest(nil, tt.base, model.Endpoint{Method: "GET", Path: tt.path}, nil, nil, nil, nil, "")
+		if err != nil {
+			t.Errorf("%s + %s: %v", tt.base, tt.path, err)
+			continue
+		}
+		if req.URL != tt.want {
+			t.Errorf("%s + %s = %s, want %s", tt.base, tt.path, req.URL, tt.want)
+		}
+	}
+}
+
+func TestBuildRequestServerPathPrefix(t *testing.T) {
+	for _, base := range []string{"https://api.example.com/v2", "https://api.example.com/v2/"} {
+		for _, p := range []string{"/users", "/users/{id}/posts"} {
+			ep := model.Endpoint{
+				Method:     "GET",
+				Path:       p,
+				PathParams: []model.Param{{Name: "id", Required: true, Type: model.TypeString}},
+			}
+			req, err := BuildRequest(nil, base, ep, map[string]string{"id": "7"}, nil, nil, nil, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "https://api.example.com/v2" + strings.Replace(p, "{id}", "7", 1)
+			if req.URL != want {
+				t.Errorf("%s + %s = %s, want %s", base, p, req.URL, want)
+			}
+		}
+	}
+}
+
+func TestFormatBodyStableKeyOrder(t *testing.T) {
+	body := []byte(`{"zeta": 1, "alpha": {"y": true, "b": null}, "mid": ["x", {"k2": 2, "k1": 1}]}`)
+	first := FormatBody("application/json", body, FormatOptions{})
+	for range 20 {
+		if got := FormatBody("application/json", body, FormatOptions{}); got != first {
+			t.Fatalf("render changed between runs:\n%s\nvs\n%s", first, got)
+		}
+	}
+	received := ansiRe.ReplaceAllString(first, "")
+	if strings.Index(received, `"zeta"`) > strings.Index(received, `"alpha"`) || strings.Index(received, `"k2"`) > strings.Index(received, `"k1"`) {
+		t.Errorf("keys not in the order received:\n%s", received)
+	}
+
+	sorted := ansiRe.ReplaceAllString(FormatBody("application/json", body, FormatOptions{SortKeys: true}), "")
+	if strings.Index(sorted, `"alpha"`) > strings.Index(sorted, `"mid"`) || strings.Index(sorted, `"mid"`) > strings.Index(sorted, `"zeta"`) ||
+		strings.Index(sorted, `"b"`) > strings.Index(sorted, `"y"`) || strings.Index(sorted, `"k1"`) > strings.Index(sorted, `"k2"`) {
+		t.Errorf("keys not sorted:\n%s", sorted)
+	}
+}
+
+func TestBuildRequestAbsolutePath(t *testing.T) {
+	ep := model.Endpoint{
+		Method:      "GET",
+		Path:        "https://other/x/{id}",
+		PathParams:  []model.Param{{Name: "id", Required: true, Type: model.TypeString}},
+		QueryParams: []model.Param{{Name: "q", Type: model.TypeString}},
+	}
+	req, err := BuildRequest(nil, "https://host/api", ep, map[string]string{"id": "7"}, map[string]string{"q": "a"}, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL != "https://other/x/7?q=a" {
+		t.Errorf("URL = %s, want the path's own absolute URL", req.URL)
+	}
+}
+
+func TestFetchOAuthPasswordTokenInvalidGrant(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" || r.FormValue("grant_type") != "password" || r.FormValue("username") != "ann" {
+			t.Errorf("unexpected token request %s %v", r.URL.Path, r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "bad username or password"}`))
+	}))
+	defer srv.Close()
+
+	_, _, err := FetchOAuthPasswordToken(context.Background(), nil, srv.URL, "/token", "ann", "wrong", "")
+	var oe *OAuthError
+	if !errors.As(err, &oe) {
+		t.Fatalf("err = %v, want an *OAuthError", err)
+	}
+	if got := err.Error(); got != "invalid_grant: bad username or password" {
+		t.Errorf("err = %q", got)
+	}
+}
+
+func TestSerializeQueryParam(t *testing.T) {
+	tests := []struct {
+		style   string
+		explode bool
+		array   string
+		object  string
+	}{
+		{"form", true, "ids=1&ids=2", "level=3&role=admin"},
+		{"form", false, "ids=1%2C2", "filter=level%2C3%2Crole%2Cadmin"},
+		{"spaceDelimited", true, "ids=1&ids=2", "level=3&role=admin"},
+		{"spaceDelimited", false, "ids=1+2", "filter=level+3+role+admin"},
+		{"pipeDelimited", true, "ids=1&ids=2", "level=3&role=admin"},
+		{"pipeDelimited", false, "ids=1%7C2", "filter=level%7C3%7Crole%7Cadmin"},
+		{"deepObject", true, "ids=1&ids=2", "filter%5Blevel%5D=3&filter%5Brole%5D=admin"},
+	}
+	for _, tt := range tests {
+		name := tt.style
+		if tt.explode {
+			name += "/explode"
+		}
+		t.Run(name, func(t *testing.T) {
+			q := url.Values{}
+			arr := model.Param{Name: "ids", Type: model.TypeArray, ItemType: model.TypeInteger, Style: tt.style, Explode: tt.explode}
+			if err := serializeQueryParam(q, arr, "1,2"); err != nil {
+				t.Fatal(err)
+			}
+			if got := q.Encode(); got != tt.array {
+				t.Errorf("array: got %s, want %s", got, tt.array)
+			}
+
+			q = url.Values{}
+			obj := model.Param{Name: "filter", Type: model.TypeObject, Style: tt.style, Explode: tt.explode}
+			if err := serializeQueryParam(q, obj, "role=admin,level=3"); err != nil {
+				t.Fatal(err)
+			}
+			if got := q.Encode(); got != tt.object {
+				t.Errorf("object: got %s, want %s", got, tt.object)
+			}
+		})
+	}
+}
+
+func TestExplodedObjectKeepsOtherParams(t *testing.T) {
+	ep := model.Endpoint{
+		Method: "GET",
+		Path:   "/users",
+		QueryParams: []model.Param{
+			{Name: "role", Type: model.TypeString},
+			{Name: "filter", Type: model.TypeObject, Style: "form", Explode: true},
+		},
+	}
+	req, err := BuildRequest(nil, "http://h", ep, nil, map[string]string{"role": "user", "filter": "role=admin,level=3"}, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if got := q["role"]; len(got) != 2 || got[0] != "user" || got[1] != "admin" {
+		t.Errorf("role = %v, want both the param and the exploded key", got)
+	}
+	if q.Get("level") != "3" {
+		t.Errorf("level = %q, want 3", q.Get("level"))
+	}
+}
+
+func TestCompactRespectsMaxDepth(t *testing.T) {
+	body := []byte(`{"a": {"b": {"c": 1}}, "list": [[1, 2]]}`)
+	got := ansiRe.ReplaceAllString(FormatBody("application/json", body, FormatOptions{Compact: true, MaxDepth: 1}), "")
+	want := `{"a": {... 1 keys}, "list": [... 1 items]}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCompactCountsKeyWidth(t *testing.T) {
+	key := strings.Repeat("k", 60)
+	body := []byte(`{"` + key + `": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10], "short": [1, 2]}`)
+	got := ansiRe.ReplaceAllString(FormatBody("application/json", body, FormatOptions{Compact: true}), "")
+	for _, line := range strings.Split(got, "\n") {
+		if utf8.RuneCountInString(line) > compactWidth {
+			t.Errorf("line is %d wide, over %d: %s", utf8.RuneCountInString(line), compactWidth, line)
+		}
+	}
+	if !strings.Contains(got, `  "short": [1, 2]`) {
+		t.Errorf("short array not inlined:\n%s", got)
+	}
+}
+
+func TestDefaultHeaderKeepsBodyContentType(t *testing.T) {
+	opts := &Options{}
+	opts.SetDefaultHeader("Content-Type", "text/plain")
+	opts.SetDefaultHeader("X-Team", "qa")
+
+	post := model.Endpoint{Method: "POST", Path: "/pets", Body: &model.BodySchema{Supported: true, MediaType: "application/json"}}
+	req, err := BuildRequest(opts, "http://h", post, nil, nil, nil, nil, `{"name": "rex"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Headers["Content-Type"]; got != "application/json" {
+		t.Errorf("Content-Type = %q, want the body's application/json", got)
+	}
+	if req.Headers["X-Team"] != "qa" {
+		t.Errorf("headers = %v, want the X-Team default", req.Headers)
+	}
+
+	get := model.Endpoint{Method: "GET", Path: "/pets"}
+	req, err = BuildRequest(opts, "http://h", get, nil, nil, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Headers["Content-Type"]; got != "text/plain" {
+		t.Errorf("Content-Type without a body = %q, want the default", got)
+	}
+}
+
+func TestRetryableError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refusedURL := "http://" + ln.Addr().String() + "/token"
+	ln.Close()
+	_, refused := http.Get(refusedURL)
+	_, badScheme := http.Get("ftp://example.com/token")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", refused, true},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{"unexpected EOF", &url.Error{Op: "Post", URL: "http://h", Err: io.ErrUnexpectedEOF}, true},
+		{"host not found", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "nope.invalid", IsNotFound: true}}, false},
+		{"unsupported scheme", badScheme, false},
+		{"canceled", &url.Error{Op: "Post", URL: "http://h", Err: context.Canceled}, false},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Fatalf("%s: no error to check", tt.name)
+		}
+		if got := retryableError(tt.err); got != tt.want {
+			t.Errorf("%s (%v): retryable = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFetchOAuthPasswordTokenRetries(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refused := "http://" + ln.Addr().String()
+	ln.Close()
+	start := time.Now()
+	if _, _, err := FetchOAuthPasswordToken(context.Background(), nil, refused, "/token", "ann", "pw", ""); err == nil {
+		t.Fatal("token fetched from a closed port")
+	}
+	// attempts wait 1x, then 2x the backoff in between
+	if elapsed := time.Since(start); elapsed < 3*tokenBackoff {
+		t.Errorf("gave up after %v, want %d attempts", elapsed, tokenAttempts)
+	}
+
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	if _, _, err := FetchOAuthPasswordToken(context.Background(), nil, srv.URL, "/token", "ann", "pw", ""); err == nil {
+		t.Fatal("401 accepted")
+	}
+	if hits != 1 {
+		t.Errorf("401 tried %d times, want once", hits)
+	}
+}
@@ -0,0 +1,204 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Options are the client settings chosen at startup (--h2c, --cacert,
+// --header, ...): how requests, token requests and spec fetches are sent, and
+// the headers every built request starts from. The zero value, like a nil
+// *Options, behaves as net/http does by default.
+type Options struct {
+	h2c        bool
+	rootCAs    *x509.CertPool
+	clientCert *tls.Certificate
+	insecure   bool
+	proxyURL   *url.URL
+
+	// noRedirects makes Execute return redirect responses instead of
+	// following them.
+	noRedirects bool
+	// accept is the Accept header sent when the endpoint doesn't set one;
+	// headers are sent with every built request (--header).
+	accept  string
+	headers map[string]string
+
+	// tlsTransport replaces http.DefaultTransport once TLS settings are
+	// customized; h2cTransport carries http:// when h2c is on.
+	tlsTransport http.RoundTripper
+	h2cTransport http.RoundTripper
+}
+
+// EnableH2C makes plaintext http:// requests (and spec fetches) use HTTP/2
+// with prior knowledge (h2c) instead of HTTP/1.1, as dev setups like
+// gRPC-gateway expect. https:// keeps the default ALPN negotiation.
+func (o *Options) EnableH2C(on bool) {
+	o.h2c = on
+	o.rebuildTransports()
+}
+
+// LoadCACert trusts the PEM certificates in path, on top of the system
+// roots, for requests and spec fetches. The file may hold several certs.
+func (o *Options) LoadCACert(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	n := 0
+	for len(data) > 0 {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("%s: certificate %d: %w", path, n+1, err)
+		}
+		pool.AddCert(cert)
+		n++
+	}
+	if n == 0 {
+		return errors.New(path + ": no PEM certificates found")
+	}
+	o.rootCAs = pool
+	o.rebuildTransports()
+	return nil
+}
+
+// LoadClientCert presents the PEM certificate/key pair for mutual TLS on
+// requests and spec fetches.
+func (o *Options) LoadClientCert(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	o.clientCert = &cert
+	o.rebuildTransports()
+	return nil
+}
+
+// SetInsecure turns TLS certificate verification off for requests and spec
+// fetches, for servers with self-signed certificates.
+func (o *Options) SetInsecure(on bool) {
+	o.insecure = on
+	o.rebuildTransports()
+}
+
+// Insecure reports whether TLS certificate verification is off.
+func (o *Options) Insecure() bool {
+	return o != nil && o.insecure
+}
+
+// SetProxy sends requests, spec fetches and token requests through the
+// proxy at raw instead of the one from HTTP_PROXY/HTTPS_PROXY/NO_PROXY.
+func (o *Options) SetProxy(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return errors.New(raw + ": proxy must be a URL like http://host:port")
+	}
+	o.proxyURL = u
+	o.rebuildTransports()
+	return nil
+}
+
+// ClientCertLoaded reports whether a client certificate is configured.
+func (o *Options) ClientCertLoaded() bool {
+	return o != nil && o.clientCert != nil
+}
+
+// SetFollowRedirects controls whether Execute follows redirects (the
+// default) or returns the 3xx response itself, Location header included.
+func (o *Options) SetFollowRedirects(on bool) {
+	o.noRedirects = !on
+}
+
+// SetDefaultAccept sets the Accept header sent with every built request,
+// e.g. "application/json". Empty sends none.
+func (o *Options) SetDefaultAccept(v string) {
+	o.accept = strings.TrimSpace(v)
+}
+
+// SetDefaultHeader adds a header sent with every built request, e.g.
+// "X-Api-Version: 2". The endpoint's own headers and header params win.
+func (o *Options) SetDefaultHeader(name, value string) {
+	if o.headers == nil {
+		o.headers = map[string]string{}
+	}
+	SetHeader(o.headers, name, value)
+}
+
+func (o *Options) rebuildTransports() {
+	o.tlsTransport, o.h2cTransport = nil, nil
+	if o.rootCAs != nil || o.clientCert != nil || o.insecure || o.proxyURL != nil {
+		o.tlsTransport = o.newTransport()
+	}
+	if o.h2c {
+		t := o.newTransport()
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetUnencryptedHTTP2(true)
+		o.h2cTransport = t
+	}
+}
+
+// newTransport clones http.DefaultTransport, which takes its proxy from the
+// environment, with the configured TLS and proxy settings.
+func (o *Options) newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if o.proxyURL != nil {
+		t.Proxy = http.ProxyURL(o.proxyURL)
+	}
+	if o.rootCAs != nil || o.clientCert != nil || o.insecure {
+		t.TLSClientConfig = &tls.Config{RootCAs: o.rootCAs, InsecureSkipVerify: o.insecure}
+	}
+	if o.clientCert != nil {
+		t.TLSClientConfig.Certificates = []tls.Certificate{*o.clientCert}
+	}
+	return t
+}
+
+// Transport is the round tripper clients in xhark should use; nil means
+// http.DefaultTransport.
+func (o *Options) Transport() http.RoundTripper {
+	if o == nil {
+		return nil
+	}
+	if o.h2cTransport == nil {
+		return o.tlsTransport
+	}
+	return schemeTransport{h2c: o.h2cTransport, tls: o.tlsTransport}
+}
+
+// schemeTransport sends http:// over h2c and everything else the usual way.
+type schemeTransport struct {
+	h2c http.RoundTripper
+	tls http.RoundTripper
+}
+
+func (t schemeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Scheme == "http" {
+		return t.h2c.RoundTrip(r)
+	}
+	if t.tls != nil {
+		return t.tls.RoundTrip(r)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
@@ -0,0 +1,133 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// IsXMLMediaType reports whether a content type is XML, including
+// structured suffixes like application/soap+xml.
+func IsXMLMediaType(ct string) bool {
+	mt, _, _ := strings.Cut(strings.ToLower(ct), ";")
+	mt = strings.TrimSpace(mt)
+	return mt == "application/xml" || mt == "text/xml" || strings.HasSuffix(mt, "+xml")
+}
+
+// colorizeXML indents an XML document two spaces per level and colors tags,
+// attributes and comments like colorizeJSON does keys and values. Elements
+// holding only text stay on one line.
+func colorizeXML(body []byte) (string, error) {
+	var toks []xml.Token
+	var open []xml.Name
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.Strict = false
+	for {
+		t, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			open = append(open, t.Name)
+		case xml.EndElement:
+			if len(open) == 0 || open[len(open)-1] != t.Name {
+				return "", errors.New("xml: unexpected </" + xmlName(t.Name) + ">")
+			}
+			open = open[:len(open)-1]
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		}
+		toks = append(toks, xml.CopyToken(t))
+	}
+	if len(open) > 0 {
+		return "", errors.New("xml: unclosed <" + xmlName(open[len(open)-1]) + ">")
+	}
+	if !slices.ContainsFunc(toks, func(t xml.Token) bool { _, ok := t.(xml.StartElement); return ok }) {
+		return "", errors.New("xml: no elements")
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(body) * 2)
+	depth := 0
+	indent := func() { sb.WriteString(strings.Repeat("  ", depth)) }
+	for i := 0; i < len(toks); i++ {
+		switch t := toks[i].(type) {
+		case xml.StartElement:
+			indent()
+			writeXMLStart(&sb, t)
+			// <a/> and <a>text</a> stay on one line
+			if i+1 < len(toks) {
+				if _, ok := toks[i+1].(xml.EndElement); ok {
+					sb.WriteString(colorKey + "/>" + colorReset + "\n")
+					i++
+					continue
+				}
+			}
+			if i+2 < len(toks) {
+				text, isText := toks[i+1].(xml.CharData)
+				_, isEnd := toks[i+2].(xml.EndElement)
+				if isText && isEnd {
+					sb.WriteString(colorKey + ">" + colorReset)
+					sb.WriteString(xmlTextEscaper.Replace(string(bytes.TrimSpace(text))))
+					sb.WriteString(colorKey + "</" + xmlName(t.Name) + ">" + colorReset + "\n")
+					i += 2
+					continue
+				}
+			}
+			sb.WriteString(colorKey + ">" + colorReset + "\n")
+			depth++
+		case xml.EndElement:
+			depth--
+			indent()
+			sb.WriteString(colorKey + "</" + xmlName(t.Name) + ">" + colorReset + "\n")
+		case xml.CharData:
+			indent()
+			sb.WriteString(xmlTextEscaper.Replace(string(bytes.TrimSpace(t))))
+			sb.WriteString("\n")
+		case xml.Comment:
+			indent()
+			sb.WriteString(colorNull + "<!--" + string(t) + "-->" + colorReset + "\n")
+		case xml.ProcInst:
+			indent()
+			sb.WriteString(colorNull + "<?" + t.Target + " " + string(t.Inst) + "?>" + colorReset + "\n")
+		case xml.Directive:
+			indent()
+			sb.WriteString(colorNull + "<!" + string(t) + ">" + colorReset + "\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+// xml.EscapeText also escapes newlines and tabs, which would flatten
+// multi-line text into &#xA;s; only what would break the markup is escaped.
+var (
+	xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	xmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// writeXMLStart writes "<name attr=value ..." without the closing bracket.
+func writeXMLStart(sb *strings.Builder, t xml.StartElement) {
+	sb.WriteString(colorKey + "<" + xmlName(t.Name) + colorReset)
+	for _, a := range t.Attr {
+		sb.WriteString(" " + colorNumber + xmlName(a.Name) + colorReset + "=" + colorString + `"`)
+		sb.WriteString(xmlAttrEscaper.Replace(a.Value))
+		sb.WriteString(`"` + colorReset)
+	}
+}
+
+// xmlName spells a raw token name as written, prefix included.
+func xmlName(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
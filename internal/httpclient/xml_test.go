@@ -0,0 +1,20 @@
+package httpclient
+
+import "testing"
+
+func TestColorizeXMLKeepsNewlines(t *testing.T) {
+	body := []byte("<note kind=\"a &amp; &quot;b&quot;\"><text>line one\nline two &lt;3</text><sig>x</sig>\n\ttail\n</note>")
+	s, err := colorizeXML(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ansiRe.ReplaceAllString(s, "")
+	want := "<note kind=\"a &amp; &quot;b&quot;\">\n" +
+		"  <text>line one\nline two &lt;3</text>\n" +
+		"  <sig>x</sig>\n" +
+		"  tail\n" +
+		"</note>\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -5,14 +5,19 @@ type ParamLocation string
 type ParamType string
 
 const (
-	ParamInPath  ParamLocation = "path"
-	ParamInQuery ParamLocation = "query"
+	ParamInPath   ParamLocation = "path"
+	ParamInQuery  ParamLocation = "query"
+	ParamInHeader ParamLocation = "header"
 
 	TypeString  ParamType = "string"
 	TypeInteger ParamType = "integer"
 	TypeNumber  ParamType = "number"
 	TypeBoolean ParamType = "boolean"
 	TypeUnknown ParamType = "unknown"
+
+	// Only query params use these; body fields of these types are unsupported.
+	TypeArray  ParamType = "array"
+	TypeObject ParamType = "object"
 )
 
 type Param struct {
@@ -24,6 +29,23 @@ type Param struct {
 	Example     string
 	Enum        []string
 	Default     string
+
+	// Style and Explode are the spec's serialization for array and object
+	// query params (form, spaceDelimited, pipeDelimited, deepObject), with
+	// the spec defaults already applied.
+	Style   string
+	Explode bool
+	// ItemType is the element type of an array param.
+	ItemType ParamType
+
+	// EnumDescriptions explains enum values, keyed by value, when the spec
+	// carries them (x-enum-descriptions and similar extensions).
+	EnumDescriptions map[string]string
+
+	// AllowEmptyValue mirrors the spec's allowEmptyValue: an explicitly empty
+	// value is sent as "?key=" instead of being skipped. For boolean query
+	// params it means "present means true": true is sent as a bare ?key.
+	AllowEmptyValue bool
 }
 
 type BodyField struct {
@@ -34,11 +56,40 @@ type BodyField struct {
 	Example     string
 	Enum        []string
 	Default     string
+
+	// Format and the constraints below are informational (shown in the
+	// builder's schema view); they aren't enforced when building a request.
+	Format    string
+	Minimum   *float64
+	Maximum   *float64
+	MinLength uint64
+	MaxLength *uint64
+	Pattern   string
 }
 
 type BodySchema struct {
 	Supported bool
+	Required  bool
 	Fields    []BodyField
+
+	// Example is a complete JSON body example declared on the media type, if any.
+	Example string
+	// Skeleton is a JSON document shaped like the full schema, nested
+	// objects and arrays included, with defaults/examples or zero values. It
+	// seeds $EDITOR for bodies the field editor can't represent.
+	Skeleton string
+	// Default is a JSON body to start from when the endpoint is opened, for
+	// sources that carry a concrete body (e.g. imported Postman requests).
+	Default string
+	// MediaType is the body's declared content type (e.g.
+	// application/merge-patch+json); empty means application/json.
+	MediaType string
+	// Text marks a text/plain body: edited and sent as is, with no fields
+	// and no JSON validation.
+	Text bool
+	// Form marks an application/x-www-form-urlencoded body: its fields are
+	// edited like JSON ones but sent URL-encoded.
+	Form bool
 }
 
 type SecurityScheme struct {
@@ -50,6 +101,10 @@ type SecurityScheme struct {
 	Scheme       string // bearer, basic, etc.
 	BearerFormat string
 
+	// apiKey: where the key goes (header, query or cookie) and under what name
+	In        string
+	ParamName string
+
 	// oauth2 password flow (MVP)
 	TokenURL string
 	Scopes   map[string]string
@@ -62,10 +117,30 @@ type Endpoint struct {
 	Path        string
 	Summary     string
 	OperationID string
+	Tags        []string
+	Deprecated  bool
+
+	PathParams   []Param
+	QueryParams  []Param
+	HeaderParams []Param
+	Body         *BodySchema
+
+	// Produces are the response media types the operation declares.
+	Produces []string
+
+	// DefaultHeaders are sent with every request to this endpoint.
+	DefaultHeaders map[string]string
+
+	// Service and BaseURL are set when endpoints from several specs share
+	// one list: the spec the endpoint came from, and where its requests go.
+	// An empty BaseURL means the app's base URL.
+	Service string
+	BaseURL string
 
-	PathParams  []Param
-	QueryParams []Param
-	Body        *BodySchema
+	// Source is an opaque handle to the operation this endpoint was extracted
+	// from. It's an escape hatch for features that need spec detail the
+	// fields above don't carry; use openapi.Operation to get at it.
+	Source any
 
 	// Security are the effective security requirements for this operation.
 	// If empty, the endpoint may still inherit global security.
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cachedSpec is a downloaded spec kept on disk, with the validators to ask
+// the server whether it changed.
+type cachedSpec struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	Body         []byte    `json:"body"`
+}
+
+var (
+	offlineMu sync.Mutex
+	// offline holds the specs last served from the cache because their
+	// server couldn't be reached, with when that copy was fetched.
+	offline = map[string]time.Time{}
+)
+
+// FromCache reports whether the last load of spec used the cached copy
+// because the server couldn't be reached, and when that copy was fetched.
+func FromCache(spec string) (time.Time, bool) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	at, ok := offline[strings.TrimSpace(spec)]
+	return at, ok
+}
+
+func setFromCache(spec string, at time.Time, ok bool) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	if ok {
+		offline[spec] = at
+	} else {
+		delete(offline, spec)
+	}
+}
+
+// specCachePath is where the copy of the spec at u lives:
+// <user cache dir>/xhark/specs/<sha256 of u>.json.
+func specCachePath(u string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(dir, "xhark", "specs", hex.EncodeToString(sum[:])+".json")
+}
+
+// readCachedSpec returns the cached copy of the spec at u; nil when there's
+// none.
+func readCachedSpec(u string) *cachedSpec {
+	p := specCachePath(u)
+	if p == "" {
+		return nil
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	var c cachedSpec
+	if json.Unmarshal(b, &c) != nil || c.URL != u || len(c.Body) == 0 {
+		return nil
+	}
+	return &c
+}
+
+// writeCachedSpec stores a downloaded spec, readable by the user only since
+// its URL may carry a token. Failing to is not an error; the next start just
+// downloads it again.
+func writeCachedSpec(u string, h http.Header, body []byte) {
+	p := specCachePath(u)
+	if p == "" {
+		return
+	}
+	c := cachedSpec{URL: u, ETag: h.Get("ETag"), LastModified: h.Get("Last-Modified"), FetchedAt: time.Now(), Body: body}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(p), 0o700) != nil {
+		return
+	}
+	_ = os.WriteFile(p, b, 0o600)
+}
@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestOnlyValidSpecsAreCached(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	body := "<html><body>Sign in</body></html>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	u := srv.URL + "/openapi.json"
+
+	if _, err := Load(context.Background(), Options{}, u); err == nil {
+		t.Fatal("an HTML page loaded as a spec")
+	}
+	if _, err := os.Stat(specCachePath(u)); !os.IsNotExist(err) {
+		t.Fatalf("the login page was cached (stat: %v)", err)
+	}
+
+	body = `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"}, "paths": {}}`
+	if _, err := Load(context.Background(), Options{}, u); err != nil {
+		t.Fatal(err)
+	}
+	if c := readCachedSpec(u); c == nil || string(c.Body) != body {
+		t.Errorf("the spec wasn't cached: %+v", c)
+	}
+}
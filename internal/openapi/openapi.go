@@ -1,6 +1,8 @@
 package openapi
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,77 +10,223 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
 
+	"xhark/internal/httpclient"
 	"xhark/internal/model"
 )
 
 const defaultTimeout = 10 * time.Second
 
-func Load(ctx context.Context, spec string) (*openapi3.T, error) {
-	client := &http.Client{Timeout: defaultTimeout}
+// Options are how specs are fetched: Client carries the transport settings
+// shared with requests (nil for net/http's defaults), and Refresh skips the
+// cached copies (--refresh); fresh downloads are still cached.
+type Options struct {
+	Client  *httpclient.Options
+	Refresh bool
+}
+
+func Load(ctx context.Context, opts Options, spec string) (*openapi3.T, error) {
+	return LoadWithOverride(ctx, opts, spec, "")
+}
+
+// LoadWithOverride loads a spec and deep-merges a local JSON or YAML override
+// file on top of it before parsing (the override wins on conflicts). This lets users
+// patch imperfect upstream specs without forking them.
+func LoadWithOverride(ctx context.Context, opts Options, spec string, override string) (*openapi3.T, error) {
+	rawBody, location, cache, err := fetchSpec(ctx, opts, spec)
+	if err != nil {
+		return nil, err
+	}
+	rawBody, err = specJSON(rawBody)
+	if err != nil {
+		return nil, err
+	}
+	if override = strings.TrimSpace(override); override != "" {
+		rawBody, err = applyOverride(rawBody, override)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rawBody, err = swaggerToV3(rawBody, location)
+	if err != nil {
+		return nil, err
+	}
+
+	// preprocess to handle openapi 3.1 numeric exclusiveMinimum/exclusiveMaximum
+	// convert them to 3.0 boolean style so kin-openapi can parse
+	processed := convertExclusiveBounds(rawBody)
+
+	loader := &openapi3.Loader{Context: ctx}
+	loader.IsExternalRefsAllowed = true
+
+	doc, err := loader.LoadFromDataWithPath(processed, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse openapi: %w", err)
+	}
+	if cache != nil {
+		cache()
+	}
+	return doc, nil
+}
+
+// DefaultSpecPaths are where Discover looks for a spec under a base URL, in
+// order: FastAPI's default, then other frameworks' usual locations.
+var DefaultSpecPaths = []string{"/openapi.json", "/swagger.json", "/v1/openapi.json", "/api-docs", "/swagger/v1/swagger.json"}
+
+// Discover probes paths under baseURL and returns the URL of the first one
+// serving a spec that loads.
+func Discover(ctx context.Context, opts Options, baseURL string, paths []string) (string, error) {
+	base := strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	var tried []string
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		u := base + "/" + strings.TrimLeft(p, "/")
+		if _, err := LoadWithOverride(ctx, opts, u, ""); err == nil {
+			return u, nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		tried = append(tried, p)
+	}
+	return "", fmt.Errorf("no spec found under %s (tried %s)", base, strings.Join(tried, ", "))
+}
+
+// fetchSpec reads the raw spec from a URL or a local file and returns it with
+// its location (used to resolve relative refs). A fresh download also comes
+// with cache, which stores it on disk; it's for the caller to call once the
+// body turned out to be a spec, not an error or login page served with 200.
+func fetchSpec(ctx context.Context, opts Options, spec string) (body []byte, location *url.URL, cache func(), err error) {
+	client := &http.Client{Timeout: defaultTimeout, Transport: opts.Client.Transport()}
 
 	spec = strings.TrimSpace(spec)
 	if strings.HasPrefix(spec, "blob:") {
 		spec = strings.TrimSpace(strings.TrimPrefix(spec, "blob:"))
 	}
 	if spec == "" {
-		return nil, fmt.Errorf("spec URL or file path required")
+		return nil, nil, nil, fmt.Errorf("spec URL or file path required")
 	}
 
 	// Local file: allow explicit @path, otherwise treat non-http(s) input as a file path.
 	if strings.HasPrefix(spec, "@") {
-		return loadFromFilePath(ctx, strings.TrimSpace(strings.TrimPrefix(spec, "@")))
+		body, location, err = readSpecFile(strings.TrimSpace(strings.TrimPrefix(spec, "@")))
+		return body, location, nil, err
 	}
 	if !strings.HasPrefix(spec, "http://") && !strings.HasPrefix(spec, "https://") {
-		return loadFromFilePath(ctx, spec)
+		body, location, err = readSpecFile(spec)
+		return body, location, nil, err
 	}
 
+	location, _ = url.Parse(spec)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, spec, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
+	}
+	// ask whether our cached copy is still current
+	var cached *cachedSpec
+	if !opts.Refresh {
+		cached = readCachedSpec(spec)
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		if cached != nil {
+			// offline: the last copy beats no spec at all
+			setFromCache(spec, cached.FetchedAt, true)
+			return cached.Body, location, nil, nil
+		}
+		return nil, nil, nil, err
 	}
 	defer resp.Body.Close()
+	setFromCache(spec, time.Time{}, false)
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.Body, location, nil, nil
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("GET %s: %s", spec, resp.Status)
+		return nil, nil, nil, fmt.Errorf("GET %s: %s", req.URL.Redacted(), resp.Status)
 	}
 
-	rawBody, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
+	body, err = gunzipSpec(body)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("GET %s: %w", req.URL.Redacted(), err)
+	}
+	cache = func() { writeCachedSpec(spec, resp.Header, body) }
+	return body, location, cache, nil
+}
 
-	// preprocess to handle openapi 3.1 numeric exclusiveMinimum/exclusiveMaximum
-	// convert them to 3.0 boolean style so kin-openapi can parse
-	processed := convertExclusiveBounds(rawBody)
-
-	loader := &openapi3.Loader{Context: ctx}
-	loader.IsExternalRefsAllowed = true
+// DocsSpecURLs lists where the spec behind a FastAPI docs page URL (/docs or
+// /redoc, as users often paste) may be, in the order to try them:
+// openapi.json next to the page, then at the origin's root, where an app
+// mounted under a prefix without root_path serves it. Other URLs and file
+// paths are returned as the only candidate.
+func DocsSpecURLs(spec string) []string {
+	u, err := url.Parse(spec)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return []string{spec}
+	}
+	dir, page := path.Split(strings.TrimSuffix(u.Path, "/"))
+	if page != "docs" && page != "redoc" {
+		return []string{spec}
+	}
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = dir + "openapi.json"
+	out := []string{u.String()}
+	if dir != "/" {
+		u.Path = "/openapi.json"
+		out = append(out, u.String())
+	}
+	return out
+}
 
-	location, _ := url.Parse(spec)
-	doc, err := loader.LoadFromDataWithPath(processed, location)
+func readSpecFile(p string) ([]byte, *url.URL, error) {
+	p = expandPath(p)
+	if p == "" {
+		return nil, nil, fmt.Errorf("spec file path required")
+	}
+	rawBody, err := os.ReadFile(p)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse openapi: %w", err)
+		return nil, nil, fmt.Errorf("read spec file %s: %w", p, err)
 	}
-
-	return doc, nil
+	rawBody, err = gunzipSpec(rawBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read spec file %s: %w", p, err)
+	}
+	return rawBody, &url.URL{Scheme: "file", Path: p}, nil
 }
 
-func loadFromFilePath(ctx context.Context, p string) (*openapi3.T, error) {
+// expandPath resolves ~ and relative paths to a clean absolute path.
+func expandPath(p string) string {
 	p = strings.TrimSpace(p)
 	if p == "" {
-		return nil, fmt.Errorf("spec file path required")
+		return ""
 	}
 	if p == "~" || strings.HasPrefix(p, "~/") {
 		h, err := os.UserHomeDir()
@@ -95,24 +243,108 @@ func loadFromFilePath(ctx context.Context, p string) (*openapi3.T, error) {
 			p = filepath.Join(cwd, p)
 		}
 	}
-	p = filepath.Clean(p)
+	return filepath.Clean(p)
+}
 
-	rawBody, err := os.ReadFile(p)
+// applyOverride deep-merges the JSON or YAML override file at p into the raw
+// spec.
+func applyOverride(raw []byte, p string) ([]byte, error) {
+	p = expandPath(p)
+	ob, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("read spec override %s: %w", p, err)
+	}
+	ob, err = specJSON(ob)
 	if err != nil {
-		return nil, fmt.Errorf("read spec file %s: %w", p, err)
+		return nil, fmt.Errorf("parse spec override %s: %w", p, err)
 	}
+	var over map[string]any
+	if err := json.Unmarshal(ob, &over); err != nil {
+		return nil, fmt.Errorf("parse spec override %s: %w", p, err)
+	}
+	var base map[string]any
+	if err := json.Unmarshal(raw, &base); err != nil {
+		return nil, fmt.Errorf("spec override needs a JSON spec: %w", err)
+	}
+	deepMerge(base, over)
+	return json.Marshal(base)
+}
 
-	processed := convertExclusiveBounds(rawBody)
+// deepMerge merges src into dst: nested objects merge key by key, anything
+// else in src replaces the value in dst.
+func deepMerge(dst, src map[string]any) {
+	for k, sv := range src {
+		if sm, ok := sv.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				deepMerge(dm, sm)
+				continue
+			}
+		}
+		dst[k] = sv
+	}
+}
 
-	loader := &openapi3.Loader{Context: ctx}
-	loader.IsExternalRefsAllowed = true
+// gunzipSpec decompresses a gzipped spec, detected by the gzip magic bytes:
+// servers that send Content-Encoding: gzip unasked, or a .json.gz file.
+// Bodies go's transport already decoded, and anything else, are returned as
+// is.
+func gunzipSpec(raw []byte) ([]byte, error) {
+	if !bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		return raw, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decompress spec: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress spec: %w", err)
+	}
+	return out, nil
+}
 
-	location := &url.URL{Scheme: "file", Path: p}
-	doc, err := loader.LoadFromDataWithPath(processed, location)
+// specJSON returns a YAML spec converted to JSON, so the JSON-only steps
+// (overrides, convertExclusiveBounds) apply to it too. JSON is returned as is.
+func specJSON(raw []byte) ([]byte, error) {
+	if json.Valid(raw) {
+		return raw, nil
+	}
+	out, err := yaml.YAMLToJSON(raw)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse openapi: %w", err)
 	}
-	return doc, nil
+	return out, nil
+}
+
+// swaggerToV3 converts a swagger 2.0 spec to openapi 3 so the rest of the
+// loader only deals with one format. Other specs are returned as is.
+//
+// A spec without schemes is served over the scheme it was fetched with
+// (location, nil for readers and files), and one with a basePath but no host
+// gets a relative server, resolved against the spec URL like any other.
+func swaggerToV3(raw []byte, location *url.URL) ([]byte, error) {
+	var probe struct {
+		Swagger string `json:"swagger"`
+	}
+	if json.Unmarshal(raw, &probe) != nil || !strings.HasPrefix(probe.Swagger, "2") {
+		return raw, nil
+	}
+	var doc2 openapi2.T
+	if err := json.Unmarshal(raw, &doc2); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger: %w", err)
+	}
+	if len(doc2.Schemes) == 0 && location != nil && (location.Scheme == "http" || location.Scheme == "https") {
+		doc2.Schemes = []string{location.Scheme}
+	}
+	doc3, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert swagger: %w", err)
+	}
+	if doc2.Host == "" && doc2.BasePath != "" {
+		doc3.AddServer(&openapi3.Server{URL: doc2.BasePath})
+	}
+	return json.Marshal(doc3)
 }
 
 // convertExclusiveBounds converts openapi 3.1 style numeric exclusiveMinimum/exclusiveMaximum
@@ -140,6 +372,18 @@ func LoadFromReader(ctx context.Context, r io.Reader) (*openapi3.T, error) {
 	if err != nil {
 		return nil, err
 	}
+	rawBody, err = gunzipSpec(rawBody)
+	if err != nil {
+		return nil, err
+	}
+	rawBody, err = specJSON(rawBody)
+	if err != nil {
+		return nil, err
+	}
+	rawBody, err = swaggerToV3(rawBody, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	processed := convertExclusiveBounds(rawBody)
 
@@ -177,6 +421,8 @@ func ExtractEndpoints(doc *openapi3.T) []model.Endpoint {
 				Path:        path,
 				Summary:     strings.TrimSpace(op.Summary),
 				OperationID: strings.TrimSpace(op.OperationID),
+				Tags:        append([]string(nil), op.Tags...),
+				Deprecated:  op.Deprecated,
 				Security:    effectiveSecurity(op.Security, doc.Security),
 			}
 
@@ -191,22 +437,48 @@ func ExtractEndpoints(doc *openapi3.T) []model.Endpoint {
 					Name:        p.Value.Name,
 					Required:    p.Value.Required,
 					Description: strings.TrimSpace(p.Value.Description),
-					Type:        schemaType(p.Value.Schema),
+					Type:        paramType(p.Value.Schema),
 					Example:     extractParamExample(p.Value),
 					Enum:        extractEnum(p.Value.Schema),
 					Default:     extractDefault(p.Value.Schema),
+
+					EnumDescriptions: extractEnumDescriptions(p.Value.Schema),
+					AllowEmptyValue:  p.Value.AllowEmptyValue,
+				}
+				if mp.Type == model.TypeArray && p.Value.Schema.Value.Items != nil {
+					mp.ItemType = schemaType(p.Value.Schema.Value.Items)
+				}
+				if sm, err := p.Value.SerializationMethod(); err == nil {
+					mp.Style, mp.Explode = sm.Style, sm.Explode
 				}
 				switch p.Value.In {
 				case "path":
+					// A path param without a matching {placeholder} is a spec
+					// error; it can't affect the URL, so don't ask for a value.
+					if !strings.Contains(path, "{"+mp.Name+"}") {
+						continue
+					}
 					mp.In = model.ParamInPath
 					ep.PathParams = append(ep.PathParams, mp)
 				case "query":
 					mp.In = model.ParamInQuery
 					ep.QueryParams = append(ep.QueryParams, mp)
+				case "header":
+					// OpenAPI says these are ignored as header params; they
+					// come from the body, negotiation and auth instead.
+					switch strings.ToLower(mp.Name) {
+					case "accept", "content-type", "authorization":
+						continue
+					}
+					mp.In = model.ParamInHeader
+					ep.HeaderParams = append(ep.HeaderParams, mp)
 				}
 			}
 
 			ep.Body = extractBody(op)
+			ep.Produces = extractProduces(op)
+			ep.DefaultHeaders = extractDefaultHeaders(op)
+			ep.Source = op
 
 			out = append(out, ep)
 		}
@@ -221,6 +493,13 @@ func ExtractEndpoints(doc *openapi3.T) []model.Endpoint {
 	return out
 }
 
+// Operation returns the source operation of an endpoint extracted by
+// ExtractEndpoints, or nil if it has none.
+func Operation(ep model.Endpoint) *openapi3.Operation {
+	op, _ := ep.Source.(*openapi3.Operation)
+	return op
+}
+
 func effectiveSecurity(opSec *openapi3.SecurityRequirements, global openapi3.SecurityRequirements) []model.SecurityRequirement {
 	// Per spec: operation.security (when present) overrides top-level.
 	if opSec != nil {
@@ -266,6 +545,8 @@ func ExtractSecuritySchemes(doc *openapi3.T) map[string]model.SecurityScheme {
 			Description:  strings.TrimSpace(ss.Description),
 			Scheme:       strings.TrimSpace(ss.Scheme),
 			BearerFormat: strings.TrimSpace(ss.BearerFormat),
+			In:           strings.TrimSpace(ss.In),
+			ParamName:    strings.TrimSpace(ss.Name),
 		}
 		if ss.Flows != nil && ss.Flows.Password != nil {
 			ms.TokenURL = strings.TrimSpace(ss.Flows.Password.TokenURL)
@@ -304,6 +585,20 @@ func schemaType(ref *openapi3.SchemaRef) model.ParamType {
 	return model.TypeUnknown
 }
 
+// paramType is schemaType plus the array and object types query params can
+// be serialized from.
+func paramType(ref *openapi3.SchemaRef) model.ParamType {
+	if ref != nil && ref.Value != nil && ref.Value.Type != nil {
+		switch {
+		case ref.Value.Type.Is("array"):
+			return model.TypeArray
+		case ref.Value.Type.Is("object"):
+			return model.TypeObject
+		}
+	}
+	return schemaType(ref)
+}
+
 func extractParamExample(p *openapi3.Parameter) string {
 	if p == nil {
 		return ""
@@ -330,6 +625,39 @@ func extractEnum(ref *openapi3.SchemaRef) []string {
 	return enums
 }
 
+// enumDescriptionKeys are the vendor extensions generators use to document
+// enum values. Either a list aligned with enum or a value -> text map.
+var enumDescriptionKeys = []string{"x-enum-descriptions", "x-enumDescriptions"}
+
+func extractEnumDescriptions(ref *openapi3.SchemaRef) map[string]string {
+	if ref == nil || ref.Value == nil || len(ref.Value.Enum) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for _, key := range enumDescriptionKeys {
+		switch d := ref.Value.Extensions[key].(type) {
+		case []any:
+			for i, e := range ref.Value.Enum {
+				if i < len(d) {
+					if s := strings.TrimSpace(fmt.Sprintf("%v", d[i])); s != "" {
+						out[fmt.Sprintf("%v", e)] = s
+					}
+				}
+			}
+		case map[string]any:
+			for v, desc := range d {
+				if s := strings.TrimSpace(fmt.Sprintf("%v", desc)); s != "" {
+					out[v] = s
+				}
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
 func extractDefault(ref *openapi3.SchemaRef) string {
 	if ref == nil || ref.Value == nil || ref.Value.Default == nil {
 		return ""
@@ -347,19 +675,37 @@ func extractSchemaExample(ref *openapi3.SchemaRef) string {
 	return ""
 }
 
+// formMediaType bodies are edited as fields and sent URL-encoded.
+const formMediaType = "application/x-www-form-urlencoded"
+
 func extractBody(op *openapi3.Operation) *model.BodySchema {
 	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
 		return nil
 	}
 
-	mt := op.RequestBody.Value.Content.Get("application/json")
+	mediaType, mt := jsonMediaType(op.RequestBody.Value.Content)
+	form := false
+	if mt == nil {
+		if mt = op.RequestBody.Value.Content.Get(formMediaType); mt != nil {
+			mediaType, form = formMediaType, true
+		}
+	}
+	if mt == nil {
+		if mt := op.RequestBody.Value.Content.Get("text/plain"); mt != nil {
+			example, _ := mt.Example.(string)
+			return &model.BodySchema{Text: true, Required: op.RequestBody.Value.Required, Example: example, MediaType: "text/plain"}
+		}
+	}
 	if mt == nil || mt.Schema == nil || mt.Schema.Value == nil {
 		return nil
 	}
 
+	example := extractMediaTypeExample(mt)
+	bodyRequired := op.RequestBody.Value.Required
+
 	s := mt.Schema.Value
 	if s.Type == nil || !s.Type.Is("object") {
-		return &model.BodySchema{Supported: false}
+		return &model.BodySchema{Supported: false, Required: bodyRequired, Example: example, Skeleton: schemaSkeleton(mt.Schema), MediaType: mediaType, Form: form}
 	}
 
 	required := map[string]bool{}
@@ -382,8 +728,186 @@ func extractBody(op *openapi3.Operation) *model.BodySchema {
 			Example:     extractSchemaExample(prop),
 			Enum:        extractEnum(prop),
 			Default:     extractDefault(prop),
+			Format:      prop.Value.Format,
+			Minimum:     prop.Value.Min,
+			Maximum:     prop.Value.Max,
+			MinLength:   prop.Value.MinLength,
+			MaxLength:   prop.Value.MaxLength,
+			Pattern:     prop.Value.Pattern,
 		})
 	}
 
-	return &model.BodySchema{Supported: supported, Fields: fields}
+	body := &model.BodySchema{Supported: supported, Required: bodyRequired, Fields: fields, Example: example, MediaType: mediaType, Form: form}
+	if !supported {
+		body.Skeleton = schemaSkeleton(mt.Schema)
+	}
+	return body
+}
+
+// maxSkeletonDepth stops schemaSkeleton on deeply nested schemas.
+const maxSkeletonDepth = 8
+
+// schemaSkeleton renders a starter JSON document for ref: objects with all
+// their properties, arrays with one element, and scalars set to their
+// default, example, first enum value or zero value. A schema nested in
+// itself (a tree of nodes) isn't expanded again.
+func schemaSkeleton(ref *openapi3.SchemaRef) string {
+	v := skeletonValue(ref, map[*openapi3.Schema]bool{})
+	if v == nil {
+		return ""
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func skeletonValue(ref *openapi3.SchemaRef, path map[*openapi3.Schema]bool) any {
+	if ref == nil || ref.Value == nil || path[ref.Value] || len(path) > maxSkeletonDepth {
+		return nil
+	}
+	s := ref.Value
+	path[s] = true
+	defer delete(path, s)
+	switch {
+	case s.Default != nil:
+		return s.Default
+	case s.Example != nil:
+		return s.Example
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+	// compositions: merge allOf, take the first oneOf/anyOf alternative
+	if len(s.AllOf) > 0 {
+		obj := map[string]any{}
+		for _, sub := range s.AllOf {
+			if m, ok := skeletonValue(sub, path).(map[string]any); ok {
+				for k, v := range m {
+					obj[k] = v
+				}
+			}
+		}
+		return obj
+	}
+	for _, alts := range []openapi3.SchemaRefs{s.OneOf, s.AnyOf} {
+		if len(alts) > 0 {
+			return skeletonValue(alts[0], path)
+		}
+	}
+	switch {
+	case s.Type == nil:
+		if len(s.Properties) == 0 {
+			return nil
+		}
+	case s.Type.Is("array"):
+		if item := skeletonValue(s.Items, path); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	case s.Type.Is("string"):
+		return ""
+	case s.Type.Is("integer"), s.Type.Is("number"):
+		return 0
+	case s.Type.Is("boolean"):
+		return false
+	case !s.Type.Is("object"):
+		return nil
+	}
+	obj := map[string]any{}
+	for name, prop := range s.Properties {
+		obj[name] = skeletonValue(prop, path)
+	}
+	return obj
+}
+
+// jsonMediaType picks the JSON request body content: application/json if
+// declared, else the first JSON-flavoured type (e.g. application/vnd.api+json).
+func jsonMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	if mt := content.Get("application/json"); mt != nil {
+		return "application/json", mt
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if httpclient.IsJSONMediaType(t) {
+			return t, content[t]
+		}
+	}
+	return "", nil
+}
+
+// extractProduces lists the media types of the operation's responses.
+func extractProduces(op *openapi3.Operation) []string {
+	if op == nil || op.Responses == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, ref := range op.Responses.Map() {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		for t := range ref.Value.Content {
+			if !seen[t] {
+				seen[t] = true
+				out = append(out, t)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// extractDefaultHeaders reads the operation's x-default-headers extension, a
+// name -> value map of headers to send with every request.
+func extractDefaultHeaders(op *openapi3.Operation) map[string]string {
+	if op == nil {
+		return nil
+	}
+	m, ok := op.Extensions["x-default-headers"].(map[string]any)
+	if !ok || len(m) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for k, v := range m {
+		if k = strings.TrimSpace(k); k != "" && v != nil {
+			out[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return out
+}
+
+// extractMediaTypeExample returns the media type's example (or the first named
+// example, in key order) as indented JSON.
+func extractMediaTypeExample(mt *openapi3.MediaType) string {
+	var v any
+	switch {
+	case mt.Example != nil:
+		v = mt.Example
+	case len(mt.Examples) > 0:
+		names := make([]string, 0, len(mt.Examples))
+		for name := range mt.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ex := mt.Examples[name]
+			if ex != nil && ex.Value != nil && ex.Value.Value != nil {
+				v = ex.Value.Value
+				break
+			}
+		}
+	}
+	if v == nil {
+		return ""
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
 }
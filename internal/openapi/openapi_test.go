@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAllowEmptyValueExtracted(t *testing.T) {
+	spec := `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {"/items": {"get": {
+		"parameters": [
+			{"name": "filter", "in": "query", "allowEmptyValue": true, "schema": {"type": "string"}},
+			{"name": "q", "in": "query", "schema": {"type": "string"}}
+		],
+		"responses": {"200": {"description": "ok"}}}}}}`
+	doc, err := LoadFromReader(context.Background(), strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eps := ExtractEndpoints(doc)
+	if len(eps) != 1 || len(eps[0].QueryParams) != 2 {
+		t.Fatalf("endpoints = %+v", eps)
+	}
+	for _, p := range eps[0].QueryParams {
+		if p.AllowEmptyValue != (p.Name == "filter") {
+			t.Errorf("%s: AllowEmptyValue = %v", p.Name, p.AllowEmptyValue)
+		}
+	}
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadGzippedSpec(t *testing.T) {
+	const spec = `{"openapi": "3.0.0", "info": {"title": "gz", "version": "1"}, "paths": {"/pets": {"get": {"responses": {"200": {"description": "ok"}}}}}}`
+	body := gzipped(t, spec)
+
+	doc, err := LoadFromReader(context.Background(), bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info.Title != "gz" {
+		t.Errorf("title = %q", doc.Info.Title)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for _, encoding := range []string{"gzip", ""} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if encoding != "" {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			_, _ = w.Write(body)
+		}))
+		doc, err := Load(context.Background(), Options{}, srv.URL+"/openapi.json")
+		srv.Close()
+		if err != nil {
+			t.Fatalf("Content-Encoding %q: %v", encoding, err)
+		}
+		if doc.Paths.Find("/pets") == nil {
+			t.Errorf("Content-Encoding %q: /pets missing", encoding)
+		}
+	}
+}
+
+func TestSwaggerServers(t *testing.T) {
+	const paths = `"paths": {"/pets": {"get": {"responses": {"200": {"description": "ok"}}}}}`
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{"host and schemes", `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "host": "api.example.com", "basePath": "/v1", "schemes": ["https"], ` + paths + `}`, "https://api.example.com/v1"},
+		{"host without schemes", `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "host": "api.example.com", "basePath": "/v1", ` + paths + `}`, "http://api.example.com/v1"},
+		{"basePath only", `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "basePath": "/api", ` + paths + `}`, "/api"},
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.spec))
+			}))
+			defer srv.Close()
+
+			doc, err := Load(context.Background(), Options{}, srv.URL+"/swagger.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(doc.Servers) != 1 {
+				t.Fatalf("got %d servers, want 1", len(doc.Servers))
+			}
+			if got := doc.Servers[0].URL; got != tt.want {
+				t.Errorf("server = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwaggerServersFromReader(t *testing.T) {
+	spec := `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "host": "api.example.com", "paths": {}}`
+	doc, err := LoadFromReader(context.Background(), strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a spec URL there's no scheme to inherit; swagger's default holds.
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://api.example.com/" {
+		t.Errorf("servers = %v", doc.Servers)
+	}
+}
+
+func TestDocsSpecURLs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"https://h/docs", []string{"https://h/openapi.json"}},
+		{"https://h/api/docs/?x=1#top", []string{"https://h/api/openapi.json", "https://h/openapi.json"}},
+		{"https://h/api/redoc", []string{"https://h/api/openapi.json", "https://h/openapi.json"}},
+		{"https://h/api/spec.yaml", []string{"https://h/api/spec.yaml"}},
+		{"./docs", []string{"./docs"}},
+	}
+	for _, tt := range tests {
+		if got := DocsSpecURLs(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DocsSpecURLs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadWithOverride(t *testing.T) {
+	for _, override := range []string{"testdata/override.yaml", "testdata/override.json"} {
+		t.Run(override, func(t *testing.T) {
+			doc, err := LoadWithOverride(context.Background(), Options{}, "testdata/petstore.yaml", override)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if doc.Info.Title != "Petstore (patched)" {
+				t.Errorf("title = %q", doc.Info.Title)
+			}
+			if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://pets.example.com/v1" {
+				t.Errorf("servers = %v", doc.Servers)
+			}
+			op := doc.Paths.Find("/pets").Get
+			// the override's keys win, the spec's others are kept
+			if op.Summary != "list all pets" || len(op.Parameters) != 1 {
+				t.Errorf("GET /pets = summary %q, %d params", op.Summary, len(op.Parameters))
+			}
+		})
+	}
+}
+
+func TestLoadWithBadOverride(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(p, []byte("info: [unclosed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadWithOverride(context.Background(), Options{}, "testdata/petstore.yaml", p)
+	if err == nil || !strings.Contains(err.Error(), "parse spec override") {
+		t.Errorf("err = %v, want a parse error naming the override", err)
+	}
+}
@@ -0,0 +1,192 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"xhark/internal/model"
+)
+
+var varRe = regexp.MustCompile(`\{\{\s*([^}]+?)\s*\}\}`)
+
+// rawURL accepts both forms Postman uses for "url": a plain string or an
+// object with a "raw" field.
+type rawURL struct {
+	URL
+}
+
+func (u *rawURL) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		u.Raw = s
+		return nil
+	}
+	return json.Unmarshal(b, &u.URL)
+}
+
+// importItem mirrors Item, but with a lenient url.
+type importItem struct {
+	Name    string `json:"name"`
+	Request *struct {
+		Method      string `json:"method"`
+		Header      []KV   `json:"header"`
+		URL         rawURL `json:"url"`
+		Body        *Body  `json:"body"`
+		Description any    `json:"description"`
+	} `json:"request"`
+	Item []importItem `json:"item"`
+}
+
+type importCollection struct {
+	Info     Info         `json:"info"`
+	Item     []importItem `json:"item"`
+	Variable []Variable   `json:"variable"`
+}
+
+// Imported is the result of loading a Postman collection. BaseURL is the
+// host of its first request; requests to other hosts carry their own in
+// Endpoint.BaseURL. Skipped lists the requests left out, as "name: reason".
+type Imported struct {
+	Name      string
+	BaseURL   string
+	Endpoints []model.Endpoint
+	Skipped   []string
+}
+
+// LoadFile reads a Postman v2.1 collection and maps its requests to
+// endpoints. Folder names become tags; collection variables are substituted
+// into URLs, headers and bodies.
+func LoadFile(p string) (*Imported, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("read postman collection %s: %w", p, err)
+	}
+	var c importCollection
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parse postman collection %s: %w", p, err)
+	}
+	vars := map[string]string{}
+	for _, v := range c.Variable {
+		vars[v.Key] = v.Value
+	}
+	out := &Imported{Name: c.Info.Name}
+	walkItems(c.Item, nil, vars, out)
+	return out, nil
+}
+
+func walkItems(items []importItem, folders []string, vars map[string]string, out *Imported) {
+	for _, it := range items {
+		if len(it.Item) > 0 {
+			walkItems(it.Item, append(append([]string(nil), folders...), it.Name), vars, out)
+			continue
+		}
+		if it.Request == nil {
+			continue
+		}
+		ep, base, err := importRequest(it, vars)
+		if err != nil {
+			out.Skipped = append(out.Skipped, it.Name+": "+err.Error())
+			continue
+		}
+		if len(folders) > 0 {
+			ep.Tags = []string{strings.Join(folders, "/")}
+		}
+		switch {
+		case out.BaseURL == "":
+			out.BaseURL = base
+		case base != out.BaseURL:
+			ep.BaseURL = base
+		}
+		out.Endpoints = append(out.Endpoints, ep)
+	}
+}
+
+func importRequest(it importItem, vars map[string]string) (model.Endpoint, string, error) {
+	r := it.Request
+	raw := substituteVars(r.URL.Raw, vars)
+	if raw == "" {
+		return model.Endpoint{}, "", fmt.Errorf("no URL")
+	}
+	// variables from a Postman environment aren't in the collection
+	if m := varRe.FindStringSubmatch(raw); m != nil {
+		return model.Endpoint{}, "", fmt.Errorf("unresolved variable {{%s}}", m[1])
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return model.Endpoint{}, "", err
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(r.Method))
+	if method == "" {
+		method = "GET"
+	}
+	ep := model.Endpoint{Method: method, Summary: it.Name, Source: it}
+
+	// ":name" segments are Postman path variables.
+	vals := map[string]string{}
+	for _, v := range r.URL.Variable {
+		vals[v.Key] = substituteVars(v.Value, vars)
+	}
+	segs := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") && len(seg) > 1 {
+			name := seg[1:]
+			segs[i] = "{" + name + "}"
+			ep.PathParams = append(ep.PathParams, model.Param{
+				Name: name, In: model.ParamInPath, Required: true, Type: model.TypeString, Example: vals[name],
+			})
+		}
+	}
+	ep.Path = "/" + strings.Join(segs, "/")
+
+	q := u.Query()
+	names := make([]string, 0, len(q))
+	for k := range q {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		ep.QueryParams = append(ep.QueryParams, model.Param{
+			Name: k, In: model.ParamInQuery, Type: model.TypeString, Example: q.Get(k),
+		})
+	}
+
+	for _, h := range r.Header {
+		if h.Disabled || strings.TrimSpace(h.Key) == "" {
+			continue
+		}
+		if ep.DefaultHeaders == nil {
+			ep.DefaultHeaders = map[string]string{}
+		}
+		ep.DefaultHeaders[h.Key] = substituteVars(h.Value, vars)
+	}
+
+	if r.Body != nil && r.Body.Mode == "raw" && strings.TrimSpace(r.Body.Raw) != "" {
+		body := substituteVars(r.Body.Raw, vars)
+		// Only JSON bodies go through the builder's body pipeline.
+		if json.Valid([]byte(body)) {
+			ep.Body = &model.BodySchema{Supported: true, Example: body, Default: body}
+		}
+	}
+
+	base := (&url.URL{Scheme: u.Scheme, User: u.User, Host: u.Host}).String()
+	return ep, base, nil
+}
+
+func substituteVars(s string, vars map[string]string) string {
+	return varRe.ReplaceAllStringFunc(s, func(m string) string {
+		name := varRe.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
@@ -0,0 +1,32 @@
+package postman
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadFileBaseURLs(t *testing.T) {
+	imp, err := LoadFile(filepath.Join("testdata", "import.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imp.BaseURL != "https://api.example.com" {
+		t.Errorf("BaseURL = %q", imp.BaseURL)
+	}
+	bases := map[string]string{}
+	for _, ep := range imp.Endpoints {
+		bases[ep.Method+" "+ep.Path] = ep.BaseURL
+	}
+	want := map[string]string{
+		"GET /users":      "",
+		"POST /token":     "https://auth.example.com",
+		"GET /users/{id}": "",
+	}
+	if !reflect.DeepEqual(bases, want) {
+		t.Errorf("endpoint base URLs = %v, want %v", bases, want)
+	}
+	if want := []string{"Invoices: unresolved variable {{billing}}"}; !reflect.DeepEqual(imp.Skipped, want) {
+		t.Errorf("Skipped = %q, want %q", imp.Skipped, want)
+	}
+}
@@ -0,0 +1,156 @@
+package postman
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"xhark/internal/model"
+)
+
+// SchemaV21 identifies the Postman collection format we read and write.
+const SchemaV21 = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is the subset of the Postman v2.1 collection schema xhark uses.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is either a request or, when Item is set, a folder of items.
+type Item struct {
+	Name    string   `json:"name"`
+	Request *Request `json:"request,omitempty"`
+	Item    []Item   `json:"item,omitempty"`
+}
+
+type Request struct {
+	Method      string `json:"method"`
+	Header      []KV   `json:"header,omitempty"`
+	URL         URL    `json:"url"`
+	Body        *Body  `json:"body,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type URL struct {
+	Raw      string     `json:"raw"`
+	Host     []string   `json:"host,omitempty"`
+	Path     []string   `json:"path,omitempty"`
+	Query    []KV       `json:"query,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+type KV struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Body struct {
+	Mode    string       `json:"mode"`
+	Raw     string       `json:"raw,omitempty"`
+	Options *BodyOptions `json:"options,omitempty"`
+}
+
+type BodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// ExportItem is one request to export: an endpoint plus optional values
+// (e.g. from a saved preset). Missing param values fall back to spec
+// examples, then defaults.
+type ExportItem struct {
+	Name       string
+	Endpoint   model.Endpoint
+	PathVals   map[string]string
+	QueryVals  map[string]string
+	HeaderVals map[string]string
+	Body       string
+}
+
+// Export serializes items as a Postman v2.1 collection. Requests use a
+// {{baseUrl}} collection variable set to baseURL.
+func Export(name, baseURL string, items []ExportItem) ([]byte, error) {
+	c := Collection{
+		Info:     Info{Name: name, Schema: SchemaV21},
+		Variable: []Variable{{Key: "baseUrl", Value: strings.TrimRight(baseURL, "/")}},
+	}
+	for _, it := range items {
+		c.Item = append(c.Item, exportItem(it))
+	}
+	return json.MarshalIndent(c, "", "  ")
+}
+
+func exportItem(it ExportItem) Item {
+	ep := it.Endpoint
+	u := URL{Host: []string{"{{baseUrl}}"}}
+
+	// Postman path variables are ":name" segments.
+	var segs []string
+	for _, seg := range strings.Split(strings.Trim(ep.Path, "/"), "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			seg = ":" + strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "}")
+		}
+		if seg != "" {
+			segs = append(segs, seg)
+		}
+	}
+	u.Path = segs
+	for _, p := range ep.PathParams {
+		u.Variable = append(u.Variable, Variable{Key: p.Name, Value: valueOr(it.PathVals, p)})
+	}
+	var qs []string
+	for _, p := range ep.QueryParams {
+		v := valueOr(it.QueryVals, p)
+		kv := KV{Key: p.Name, Value: v, Disabled: v == "" && !p.Required}
+		u.Query = append(u.Query, kv)
+		if !kv.Disabled {
+			qs = append(qs, url.QueryEscape(p.Name)+"="+url.QueryEscape(v))
+		}
+	}
+	u.Raw = "{{baseUrl}}/" + strings.Join(segs, "/")
+	if len(qs) > 0 {
+		u.Raw += "?" + strings.Join(qs, "&")
+	}
+
+	req := &Request{Method: ep.Method, URL: u, Description: ep.Summary}
+	for _, p := range ep.HeaderParams {
+		v := valueOr(it.HeaderVals, p)
+		req.Header = append(req.Header, KV{Key: p.Name, Value: v, Disabled: v == "" && !p.Required})
+	}
+	if body := strings.TrimSpace(it.Body); body != "" {
+		req.Header = append(req.Header, KV{Key: "Content-Type", Value: "application/json"})
+		b := &Body{Mode: "raw", Raw: body, Options: &BodyOptions{}}
+		b.Options.Raw.Language = "json"
+		req.Body = b
+	}
+
+	name := it.Name
+	if name == "" {
+		name = ep.Method + " " + ep.Path
+	}
+	return Item{Name: name, Request: req}
+}
+
+func valueOr(vals map[string]string, p model.Param) string {
+	if v := strings.TrimSpace(vals[p.Name]); v != "" {
+		return v
+	}
+	if p.Example != "" {
+		return p.Example
+	}
+	return p.Default
+}
@@ -0,0 +1,59 @@
+package postman
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xhark/internal/model"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestExportGolden(t *testing.T) {
+	ep := model.Endpoint{
+		Method:  "POST",
+		Path:    "/pets/{id}/toys",
+		Summary: "add toy",
+		PathParams: []model.Param{
+			{Name: "id", In: "path", Required: true, Example: "7"},
+		},
+		QueryParams: []model.Param{
+			{Name: "q", In: "query"},
+			{Name: "tag", In: "query", Default: "a&b c"},
+		},
+		HeaderParams: []model.Param{
+			{Name: "X-Trace", In: "header"},
+		},
+	}
+	items := []ExportItem{
+		{Endpoint: ep},
+		{
+			Name:       "add toy (preset)",
+			Endpoint:   ep,
+			PathVals:   map[string]string{"id": "42"},
+			QueryVals:  map[string]string{"q": "x=y"},
+			HeaderVals: map[string]string{"X-Trace": "abc"},
+			Body:       `{"name":"ball"}`,
+		},
+	}
+	got, err := Export("Pets", "http://localhost:8000/", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "export.golden.json")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("export differs from %s (run with -update to accept):\n%s", golden, got)
+	}
+}
@@ -4,25 +4,33 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/jroimartin/gocui"
 
+	"xhark/internal/config"
+	"xhark/internal/curl"
+	"xhark/internal/har"
 	"xhark/internal/httpclient"
 	"xhark/internal/model"
 	"xhark/internal/openapi"
+	"xhark/internal/postman"
 )
 
 var debugLog *log.Logger
@@ -30,8 +38,7 @@ var debugLog *log.Logger
 func init() {
 	// enable debug logging with XHARK_DEBUG=1
 	if os.Getenv("XHARK_DEBUG") == "1" {
-		f, err := os.OpenFile("/tmp/xhark.log", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-		if err == nil {
+		if f, err := openDebugLog(); err == nil {
 			debugLog = log.New(f, "", log.LstdFlags)
 			return
 		}
@@ -39,12 +46,29 @@ func init() {
 	debugLog = log.New(io.Discard, "", 0)
 }
 
+// openDebugLog creates <user cache dir>/xhark/xhark.log, readable by the user
+// only: it holds request and response bodies, credentials included.
+func openDebugLog() (*os.File, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Join(dir, "xhark")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(dir, "xhark.log"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+}
+
 type screen int
 
 const (
 	screenEndpoints screen = iota
 	screenBuilder
 	screenResponse
+	screenInspect
+	screenHistory
+	screenCompare
 )
 
 type focusPane int
@@ -52,9 +76,30 @@ type focusPane int
 const (
 	panePath focusPane = iota
 	paneQuery
+	paneHeader
 	paneBody
 )
 
+// paneNames are the builder view names of each pane.
+var paneNames = map[focusPane]string{
+	panePath:   "path",
+	paneQuery:  "query",
+	paneHeader: "header",
+	paneBody:   "body",
+}
+
+type facetKind int
+
+const (
+	facetMethod facetKind = iota
+	facetTag
+)
+
+type facet struct {
+	kind  facetKind
+	value string
+}
+
 type authState struct {
 	schemeName string
 	token      string
@@ -69,6 +114,7 @@ const (
 	authModeUser
 	authModePass
 	authModeScope
+	authModeApiKey
 )
 
 type App struct {
@@ -79,25 +125,90 @@ type App struct {
 
 	scr screen
 
-	specURL    string
-	baseURL    string
+	specURL      string
+	specOverride string
+	// specPaths are probed under the base URL when no spec is given.
+	specPaths   []string
+	postmanFile string
+	baseURL     string
+	// client holds the transport and default-header settings every request,
+	// token request and spec fetch is sent with; refresh skips the spec
+	// cache (--refresh).
+	client     httpclient.Options
+	refresh    bool
 	endpoints  []model.Endpoint
 	secSchemes map[string]model.SecurityScheme
+	// servers are the spec's declared servers (ctrl+s picks one);
+	// serverChoice is the one whose variables are being asked for.
+	servers      []server
+	serverChoice *serverChoice
+
+	specTitle string
+
+	// specs holds every loaded API when more than one spec was given;
+	// the fields above mirror specs[activeSpec].
+	extraSpecs []string
+	specs      []*loadedSpec
+	activeSpec int
+
+	cfg *config.Config
+	// authFile persists acquired tokens across restarts; nil when
+	// XHARK_AUTH_STORE turns it off.
+	authFile *config.AuthStore
 
 	filter   string
 	filtered []int
 	selected int
 
+	// hideBadges hides the deprecated/auth/body badges in the endpoint list.
+	hideBadges bool
+	// hideDeprecated leaves deprecated operations out of the endpoint list.
+	hideDeprecated bool
+
+	// grouped lists endpoints under collapsible tag headers; selected then
+	// indexes rows rather than filtered.
+	grouped   bool
+	rows      []endpointRow
+	collapsed map[string]bool
+
+	// Quick-filter chips (methods, then tags) shown above the endpoint list.
+	facets       []facet
+	facetCursor  int
+	activeFacets map[facet]bool
+
 	activeEndpoint model.Endpoint
 	pathVals       map[string]string
 	queryVals      map[string]string
+	headerVals     map[string]string
 	bodyVals       map[string]string
 	bodyRaw        string
+	// bodyDraft holds editor content that failed to parse, so it can be fixed;
+	// bodyDraftErr is the parse error shown above it when it's reopened.
+	bodyDraft    string
+	bodyDraftErr string
 
 	pane focusPane
 
+	// showRawPath shows the path template instead of the resolved preview in
+	// the selected endpoint panel.
+	showRawPath bool
+	// maximized gives the focused builder pane the full height.
+	maximized bool
+
 	editing    bool
 	editTarget string
+	// width and height are the screen size of the last layout; the edit
+	// modal is placed against them when it opens.
+	width, height int
+	// curlLines holds the lines of a multi-line curl command pasted so far;
+	// the edit modal only ever shows the current one.
+	curlLines string
+
+	picker *picker
+
+	// preview is the request being reviewed in the header preview (h)
+	// before it's sent; edits there apply to it directly.
+	preview *httpclient.RequestSpec
 
 	// Auth dialog state
 	authOpen       bool
@@ -111,38 +222,323 @@ type App struct {
 	authPassword   string
 	authScope      string
 	authError      string
-	authStore      map[string]authState
+	// tokenFetchStarted is when the running OAuth token request was sent;
+	// zero when none is.
+	tokenFetchStarted time.Time
+	authStore         map[string]authState
+	// authProfiles are named credential sets (e.g. "admin", "readonly") to
+	// compare authorization with; authStore is authProfiles[authProfile].
+	authProfiles map[string]map[string]authState
+	authProfile  string
+	// noAuth sends every request without credentials (--no-auth); skipAuth
+	// is the builder's per-request toggle, reset to noAuth on each endpoint.
+	noAuth   bool
+	skipAuth bool
+	// checkServer probes the base URLs once the UI is up (--check-server).
+	checkServer bool
+	// cacheResponses answers a request identical to one already sent this
+	// session from history (--cache); resCached marks such a response.
+	cacheResponses bool
+	resCached      bool
 
 	suspendEditorFile string
+	// notesFor is the endpoint key whose notes are open in $EDITOR.
+	notesFor string
+	// noEditor edits body fields inline instead of suspending into $EDITOR.
+	noEditor bool
+	// jsonc accepts comments and trailing commas in edited bodies.
+	jsonc bool
+	// bodySchemaView shows the body fields' schema instead of their values.
+	bodySchemaView bool
+	// initialBody pre-fills the body of every endpoint that takes one
+	// (--body-env).
+	initialBody string
+	// mergeSpecs lists the endpoints of every loaded spec in one list.
+	mergeSpecs bool
+	// reloading is set while F5 loads the spec again in the background.
+	reloading bool
+
+	// timeout bounds each request; methodTimeouts override it per method.
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
+	// warnSlow flags responses that took longer than this (--warn-slow).
+	warnSlow time.Duration
+	// logPretty indents JSON bodies in the debug log instead of logging them raw.
+	logPretty bool
 
 	lastReq  httpclient.RequestSpec
 	lastRes  httpclient.Result
 	errorMsg string
+
+	// inFlight is the request being sent, if any; see send.
+	inFlight *inFlight
+
+	// toast is a transient footer message set via notify; it takes
+	// precedence over errorMsg until it expires.
+	toast    string
+	toastSeq int
+
+	// resErr is set when the last send got no response at all (refused,
+	// timeout, DNS...); the response screen shows it instead of lastRes.
+	resErr error
+
+	// prevRes is the response replaced by the last rerun, kept for diffing.
+	prevRes  *httpclient.Result
+	showDiff bool
+
+	// history records every request sent this session, newest last, for the
+	// history screen and HAR export. historySel is the row selected there
+	// (0 = newest) and historyFrom the screen to return to.
+	history     []historyEntry
+	historySel  int
+	historyFrom screen
+	historySeq  int
+	// compareMark is the history entry picked as the left side of a
+	// comparison (nil for none); cmpLeft and cmpRight are the entries on the
+	// compare screen, cmpDiff what differs between them, cmpText each side
+	// rendered, and comparing is set while both are being sent again.
+	compareMark *historyEntry
+	cmpLeft     historyEntry
+	cmpRight    historyEntry
+	cmpDiff     []string
+	cmpText     [2]string
+	comparing   bool
+
+	// Response bodies beyond maxBodyBytes are shown truncated (and not
+	// colorized) until expanded. resBody caches the rendered body.
+	maxBodyBytes int
+	resExpanded  bool
+	resBody      string
+	// resSearch is the term searched for in the response (/); resMatches
+	// are the view lines it's on, set on render, and resMatch the current.
+	resSearch  string
+	resMatches []int
+	resMatch   int
+	jsonFormat httpclient.FormatOptions
 }
 
+const defaultMaxBodyKB = 512
+
 func NewApp(in io.Reader, out io.Writer) *App {
-	return &App{in: in, out: out, scr: screenEndpoints, authStore: map[string]authState{}}
+	return &App{in: in, out: out, scr: screenEndpoints, authStore: map[string]authState{}, maxBodyBytes: defaultMaxBodyKB * 1024}
 }
 
 func (a *App) SetSpec(spec string) {
 	a.specURL = strings.TrimSpace(spec)
 }
 
+// SetSpecPaths replaces the paths probed under the base URL when no spec is
+// given (default openapi.DefaultSpecPaths).
+func (a *App) SetSpecPaths(paths []string) {
+	a.specPaths = paths
+}
+
+// SetPostmanFile loads endpoints from a Postman v2.1 collection instead of an
+// OpenAPI spec.
+func (a *App) SetPostmanFile(p string) {
+	a.postmanFile = strings.TrimSpace(p)
+}
+
+// SetSpecOverride sets a local JSON or YAML file that is deep-merged over the
+// spec when it's loaded.
+func (a *App) SetSpecOverride(p string) {
+	a.specOverride = strings.TrimSpace(p)
+}
+
 func (a *App) SetBaseURL(baseURL string) {
 	a.baseURL = normalizeBaseURL(baseURL)
 }
 
+// SetLogPretty indents JSON bodies in the debug log (XHARK_DEBUG=1). By
+// default bodies are logged exactly as sent and received.
+func (a *App) SetLogPretty(pretty bool) {
+	a.logPretty = pretty
+}
+
+// SetMergeSpecs lists the endpoints of all loaded specs in one list instead
+// of switching between them.
+func (a *App) SetMergeSpecs(merge bool) {
+	a.mergeSpecs = merge
+}
+
+// SetTimeout sets the default request timeout.
+func (a *App) SetTimeout(d time.Duration) {
+	a.timeout = d
+}
+
+// SetMethodTimeout sets the request timeout for one HTTP method, overriding
+// the default.
+func (a *App) SetMethodTimeout(method string, d time.Duration) {
+	if a.methodTimeouts == nil {
+		a.methodTimeouts = map[string]time.Duration{}
+	}
+	a.methodTimeouts[strings.ToUpper(method)] = d
+}
+
+// SetWarnSlow highlights response times above d: yellow past d, red past
+// twice d.
+func (a *App) SetWarnSlow(d time.Duration) {
+	a.warnSlow = d
+}
+
+// SetH2C sends plaintext requests over HTTP/2 with prior knowledge (h2c).
+func (a *App) SetH2C(on bool) {
+	a.client.EnableH2C(on)
+}
+
+// SetCACert trusts the PEM certificates in path, alongside the system
+// roots, when loading specs and sending requests.
+func (a *App) SetCACert(path string) error {
+	return a.client.LoadCACert(path)
+}
+
+// SetClientCert presents a client certificate for mutual TLS when loading
+// specs and sending requests.
+func (a *App) SetClientCert(certFile, keyFile string) error {
+	return a.client.LoadClientCert(certFile, keyFile)
+}
+
+// SetNoAuth sends requests without credentials, even to endpoints that
+// require them; the builder can still toggle auth back on per request.
+func (a *App) SetNoAuth(on bool) {
+	a.noAuth = on
+}
+
+// SetCacheResponses shows the last response to an identical request from
+// this session instead of sending it again.
+func (a *App) SetCacheResponses(on bool) {
+	a.cacheResponses = on
+}
+
+// SetRefresh downloads specs anew instead of revalidating the copies cached
+// on disk.
+func (a *App) SetRefresh(on bool) {
+	a.refresh = on
+}
+
+// specOptions are the settings specs are fetched with.
+func (a *App) specOptions() openapi.Options {
+	return openapi.Options{Client: &a.client, Refresh: a.refresh}
+}
+
+// SetCheckServer warns, without blocking startup, when a spec's base URL
+// can't be reached.
+func (a *App) SetCheckServer(on bool) {
+	a.checkServer = on
+}
+
+// SetProxy sends all traffic through the proxy at raw.
+func (a *App) SetProxy(raw string) error {
+	return a.client.SetProxy(raw)
+}
+
+// SetInsecure skips TLS certificate verification when loading specs and
+// sending requests.
+func (a *App) SetInsecure(on bool) {
+	a.client.SetInsecure(on)
+}
+
+// SetNoRedirects shows redirect responses as they are instead of following
+// them.
+func (a *App) SetNoRedirects(on bool) {
+	a.client.SetFollowRedirects(!on)
+}
+
+// SetHeader adds a default header sent with every request; the endpoint's
+// own headers, header params and auth take precedence.
+func (a *App) SetHeader(name, value string) {
+	a.client.SetDefaultHeader(name, value)
+}
+
+// SetAccept sets the default Accept header for every request; headers the
+// endpoint itself sets take precedence.
+func (a *App) SetAccept(v string) {
+	a.client.SetDefaultAccept(v)
+}
+
+// SetInitialBody pre-fills the JSON body of any endpoint opened in the
+// builder. The body must be valid JSON.
+func (a *App) SetInitialBody(body string) error {
+	body = strings.TrimSpace(body)
+	var check any
+	if err := json.Unmarshal([]byte(body), &check); err != nil {
+		return fmt.Errorf("invalid json body: %w", err)
+	}
+	a.initialBody = body
+	return nil
+}
+
+// SetNoEditor switches body editing from $EDITOR to the inline field editor.
+func (a *App) SetNoEditor(noEditor bool) {
+	a.noEditor = noEditor
+}
+
+// SetJSONC allows comments and trailing commas when editing bodies; they're
+// stripped before the body is stored.
+func (a *App) SetJSONC(jsonc bool) {
+	a.jsonc = jsonc
+}
+
+// SetJSONDepth collapses JSON response nodes nested deeper than depth.
+func (a *App) SetJSONDepth(depth int) {
+	a.jsonFormat.MaxDepth = depth
+}
+
+// SetJSONMaxItems truncates JSON response arrays to their first n elements.
+func (a *App) SetJSONMaxItems(n int) {
+	a.jsonFormat.MaxItems = n
+}
+
+// SetMaxBodyKB sets how much of a response body is rendered before it is
+// truncated behind the expand key.
+func (a *App) SetMaxBodyKB(kb int) {
+	if kb > 0 {
+		a.maxBodyBytes = kb * 1024
+	}
+}
+
 // Init loads the OpenAPI spec and prepares the endpoint list.
 func (a *App) Init() error {
-	if strings.TrimSpace(a.specURL) == "" {
-		return fmt.Errorf("spec required (use --spec-url or --spec-file, or set XHARK_SPEC_URL/XHARK_SPEC_FILE)")
+	if strings.TrimSpace(a.specURL) == "" && a.postmanFile == "" && isHTTPURL(a.baseURL) {
+		if err := a.discoverSpec(); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(a.specURL) == "" && a.postmanFile == "" {
+		return fmt.Errorf("spec required (use --spec-url, --spec-file, --postman-file or --base-url, or set XHARK_SPEC_URL/XHARK_SPEC_FILE)")
 	}
 
-	if strings.HasPrefix(a.specURL, "http://") || strings.HasPrefix(a.specURL, "https://") {
-		a.baseURL = baseURLFromURLSpec(a.specURL)
+	// A broken config shouldn't stop the app; start from an empty one.
+	cfg, err := config.Load()
+	if err != nil {
+		debugLog.Printf("config: %v", err)
+	}
+	a.cfg = cfg
+	authFile, err := config.LoadAuth()
+	if err != nil {
+		debugLog.Printf("auth store: %v", err)
 	}
+	a.authFile = authFile
+
+	return a.loadSpecs()
+}
 
-	return a.loadEndpoints()
+// discoverSpec finds the spec under the base URL when only that was given.
+func (a *App) discoverSpec() error {
+	paths := a.specPaths
+	if len(paths) == 0 {
+		paths = openapi.DefaultSpecPaths
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	u, err := openapi.Discover(ctx, a.specOptions(), a.baseURL, paths)
+	if err != nil {
+		return err
+	}
+	debugLog.Printf("spec found at %s", u)
+	a.specURL = u
+	a.notify("spec found at " + u)
+	return nil
 }
 
 // singleLineEditor is an editor that doesn't consume Enter (lets keybinding handle it)
@@ -192,11 +588,23 @@ func (e singleLineEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.
 		v.SetCursor(len(line)-1, 0)
 	case key == gocui.KeyEnter:
 		// don't handle - let keybinding process it
+	case key == gocui.KeySpace:
+		v.EditWrite(' ')
 	case ch != 0 && mod == 0:
 		v.EditWrite(ch)
 	}
 }
 
+// typeInto hands r to v's editor when v takes input, for global hotkeys that
+// are also letters; it reports whether it did.
+func typeInto(v *gocui.View, r rune) bool {
+	if v == nil || !v.Editable || v.Editor == nil {
+		return false
+	}
+	v.Editor.Edit(v, 0, r, gocui.ModNone)
+	return true
+}
+
 func (a *App) Run() error {
 	// We sometimes need to temporarily drop out of the TUI to run an external
 	// process (e.g. $EDITOR for JSON body editing). gocui doesn't expose a native
@@ -221,6 +629,14 @@ func (a *App) Run() error {
 			g.Close()
 			return err
 		}
+		if a.checkServer {
+			a.checkServer = false
+			go a.checkServers(g, a.serverBases())
+		}
+		if a.toast != "" {
+			// set before the GUI existed (e.g. by Init); start its timer
+			a.notify(a.toast)
+		}
 
 		err = g.MainLoop()
 		g.Close()
@@ -243,20 +659,40 @@ func (a *App) Run() error {
 	}
 }
 
+// minLayoutWidth and minLayoutHeight are the smallest terminal the screens
+// are laid out in; below that only a notice is shown.
+const (
+	minLayoutWidth  = 40
+	minLayoutHeight = 12
+)
+
 func (a *App) layout(g *gocui.Gui) error {
-	maxX, maxY := g.Size()
+	return a.layoutSize(g.Size())
+}
+
+// layoutSize lays the current screen out on a maxX by maxY terminal.
+func (a *App) layoutSize(maxX, maxY int) error {
+	if maxX < minLayoutWidth || maxY < minLayoutHeight {
+		return a.layoutTooSmall(maxX, maxY)
+	}
+	a.g.DeleteView("too-small")
+	a.width, a.height = maxX, maxY
 
-	if v, err := g.SetView("header", 0, 0, maxX-1, 2); err != nil {
+	if v, err := a.g.SetView("title", 0, 0, maxX-1, 2); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
 		v.Frame = false
 		v.BgColor = gocui.ColorBlack
 		v.FgColor = gocui.ColorWhite
-		fmt.Fprintln(v, colorGreen+"xhark"+colorReset+"  -  OpenAPI TUI")
+		title := colorGreen + "xhark" + colorReset + "  -  OpenAPI TUI"
+		if a.client.Insecure() {
+			title += "  " + colorRed + "[insecure: TLS not verified]" + colorReset
+		}
+		fmt.Fprintln(v, title)
 	}
 
-	if v, err := g.SetView("footer", 0, maxY-2, maxX-1, maxY); err != nil {
+	if v, err := a.g.SetView("footer", 0, maxY-2, maxX-1, maxY); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -267,19 +703,77 @@ func (a *App) layout(g *gocui.Gui) error {
 	a.renderFooter()
 
 	if a.authOpen {
-		return a.layoutAuth(maxX, maxY)
+		if err := a.layoutAuth(maxX, maxY); err != nil {
+			return err
+		}
+		if a.editing {
+			if err := a.layoutEdit(); err != nil {
+				return err
+			}
+		}
+		if a.picker != nil {
+			return a.layoutPicker(maxX, maxY)
+		}
+		return nil
 	}
 
+	var err error
 	switch a.scr {
 	case screenEndpoints:
-		return a.layoutEndpoints(maxX, maxY)
+		err = a.layoutEndpoints(maxX, maxY)
 	case screenBuilder:
-		return a.layoutBuilder(maxX, maxY)
+		err = a.layoutBuilder(maxX, maxY)
 	case screenResponse:
-		return a.layoutResponse(maxX, maxY)
-	default:
+		err = a.layoutResponse(maxX, maxY)
+	case screenInspect:
+		err = a.layoutInspect(maxX, maxY)
+	case screenHistory:
+		err = a.layoutHistory(maxX, maxY)
+	case screenCompare:
+		err = a.layoutCompare(maxX, maxY)
+	}
+	if err != nil {
+		return err
+	}
+	if a.editing {
+		if err := a.layoutEdit(); err != nil {
+			return err
+		}
+	}
+	if a.picker != nil {
+		return a.layoutPicker(maxX, maxY)
+	}
+	return nil
+}
+
+// layoutTooSmall covers the screen with a notice until the terminal grows
+// back; the views underneath keep their state.
+func (a *App) layoutTooSmall(maxX, maxY int) error {
+	if maxX < 2 || maxY < 2 {
 		return nil
 	}
+	v, err := a.g.SetView("too-small", -1, -1, maxX, maxY)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = false
+		v.BgColor = gocui.ColorBlack
+		v.FgColor = gocui.ColorWhite
+	}
+	v.Clear()
+	// pad every row so nothing underneath shows through
+	lines := []string{"terminal too small", fmt.Sprintf("need %dx%d", minLayoutWidth, minLayoutHeight)}
+	for y := 0; y < maxY; y++ {
+		line := ""
+		if y < len(lines) {
+			line = lines[y]
+		}
+		fmt.Fprintln(v, padRight(truncateVisible(line, maxX), maxX))
+	}
+	a.g.SetViewOnTop("too-small")
+	_, err = a.g.SetCurrentView("too-small")
+	return err
 }
 
 func (a *App) layoutAuth(maxX, maxY int) error {
@@ -348,6 +842,7 @@ func (a *App) layoutAuth(maxX, maxY int) error {
 			return err
 		}
 		v.Title = "Details"
+		v.Wrap = true
 		v.Editable = false
 		v.Editor = singleLineEditor{}
 	}
@@ -355,25 +850,38 @@ func (a *App) layoutAuth(maxX, maxY int) error {
 	// render
 	a.renderAuth()
 
-	// focus
-	if a.authEditing {
+	// z-order: box at back, then list/form on top
+	_, _ = a.g.SetViewOnTop("auth-box")
+	_, _ = a.g.SetViewOnTop("auth-schemes")
+	_, _ = a.g.SetViewOnTop("auth-form")
+
+	// focus; the profile picker and its name prompt sit above the modal
+	switch {
+	case a.editing:
+		_, _ = a.g.SetViewOnTop("edit")
+		if _, err := a.g.SetCurrentView("edit"); err != nil {
+			return err
+		}
+	case a.picker != nil:
+	case a.authEditing:
 		if _, err := a.g.SetCurrentView("auth-form"); err != nil {
 			return err
 		}
-	} else {
+	default:
 		if _, err := a.g.SetCurrentView("auth-schemes"); err != nil {
 			return err
 		}
 	}
-	// z-order: box at back, then list/form on top
-	_, _ = a.g.SetViewOnTop("auth-box")
-	_, _ = a.g.SetViewOnTop("auth-schemes")
-	_, _ = a.g.SetViewOnTop("auth-form")
 	return nil
 }
 
 func (a *App) layoutEndpoints(maxX, maxY int) error {
-	a.clearMainViews([]string{"filter", "endpoints"})
+	keep := []string{"filter", "facets", "endpoints"}
+	if a.editing {
+		// prompts opened from the list (e.g. curl import)
+		keep = append(keep, "edit")
+	}
+	a.clearMainViews(keep)
 
 	if v, err := a.g.SetView("filter", 0, 2, maxX-1, 4); err != nil {
 		if err != gocui.ErrUnknownView {
@@ -382,18 +890,39 @@ func (a *App) layoutEndpoints(maxX, maxY int) error {
 		v.Title = "Filter"
 		v.Editable = false
 	}
-	if v, err := a.g.SetView("endpoints", 0, 4, maxX-1, maxY-3); err != nil {
+	if v, err := a.g.SetView("facets", 0, 4, maxX-1, 6); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Facets (left/right, ctrl+t)"
+		v.Editable = false
+	}
+	if v, err := a.g.SetView("endpoints", 0, 6, maxX-1, maxY-3); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		v.Title = "Endpoints"
 		v.Highlight = true
 		v.SelFgColor = gocui.ColorBlack
 		v.SelBgColor = gocui.ColorGreen
 		v.Autoscroll = false
 	}
+	if v, err := a.g.View("endpoints"); err == nil {
+		v.Title = "Endpoints"
+		if len(a.specs) > 1 {
+			v.Title = fmt.Sprintf("Endpoints: %s (%d/%d, ctrl+o to switch)", firstNonEmpty(a.specTitle, a.specURL), a.activeSpec+1, len(a.specs))
+		}
+		if a.grouped {
+			v.Title += " by tag (enter on a tag: collapse)"
+		}
+	}
 	a.renderFilter()
+	a.renderFacets()
 	a.renderEndpoints()
+	if a.editing {
+		a.g.SetViewOnTop("edit")
+		a.g.SetCurrentView("edit")
+		return nil
+	}
 	if _, err := a.g.SetCurrentView("endpoints"); err != nil {
 		return err
 	}
@@ -401,40 +930,30 @@ func (a *App) layoutEndpoints(maxX, maxY int) error {
 }
 
 func (a *App) layoutBuilder(maxX, maxY int) error {
-	// determine which panels to show
-	hasPath := len(a.activeEndpoint.PathParams) > 0
-	hasQuery := len(a.activeEndpoint.QueryParams) > 0
-	hasBody := a.activeEndpoint.Body != nil
-
 	// build list of panels to display
 	var panels []string
-	if hasPath {
-		panels = append(panels, "path")
-	}
-	if hasQuery {
-		panels = append(panels, "query")
-	}
-	if hasBody {
-		panels = append(panels, "body")
+	for _, p := range a.builderPanes() {
+		panels = append(panels, paneNames[p])
 	}
-
-	// fallback: show at least path panel with "(none)" message
-	if len(panels) == 0 {
-		panels = []string{"path"}
-		hasPath = true
+	if a.maximized {
+		a.ensureValidPane()
+		panels = []string{paneNames[a.pane]}
 	}
 
 	// clear views that won't be shown, but keep edit modal if active
 	keepViews := make([]string, len(panels))
 	copy(keepViews, panels)
 	keepViews = append(keepViews, "selected")
+	if a.endpointNote() != "" {
+		keepViews = append(keepViews, "notes")
+	}
 	if a.editing {
 		keepViews = append(keepViews, "edit")
 	}
 	a.clearMainViews(keepViews)
 
 	// ensure current pane is valid
-	a.ensureValidPane(hasPath, hasQuery, hasBody)
+	a.ensureValidPane()
 
 	// add selected endpoint panel at top
 	if v, err := a.g.SetView("selected", 0, 2, maxX-1, 4); err != nil {
@@ -442,10 +961,17 @@ func (a *App) layoutBuilder(maxX, maxY int) error {
 			return err
 		}
 		v.Title = "Selected endpoint"
+		if a.client.ClientCertLoaded() {
+			v.Title += " (mTLS)"
+		}
 	}
 
-	bodyTop := 4
+	// the notes give way before the panes get under two rows each
 	paramsBottom := maxY - 3
+	bodyTop, err := a.layoutNotes(maxX, 4, paramsBottom-4-2*len(panels))
+	if err != nil {
+		return err
+	}
 	panelHeight := (paramsBottom - bodyTop) / len(panels)
 
 	for i, panel := range panels {
@@ -465,6 +991,11 @@ func (a *App) layoutBuilder(maxX, maxY int) error {
 
 	a.renderBuilder()
 	a.updatePanelColors()
+	if a.maximized {
+		if v, err := a.g.View(paneNames[a.pane]); err == nil {
+			v.Title += " (maximized, z to restore)"
+		}
+	}
 
 	// if editing, ensure edit view is on top and focused
 	if a.editing {
@@ -479,34 +1010,38 @@ func (a *App) layoutBuilder(maxX, maxY int) error {
 	return nil
 }
 
-func (a *App) ensureValidPane(hasPath, hasQuery, hasBody bool) {
+// builderPanes lists the panes the active endpoint has, in tab order. With
+// none, the path pane is shown with a "(none)" message.
+func (a *App) builderPanes() []focusPane {
+	ep := a.activeEndpoint
+	var panes []focusPane
+	if len(ep.PathParams) > 0 {
+		panes = append(panes, panePath)
+	}
+	if len(ep.QueryParams) > 0 {
+		panes = append(panes, paneQuery)
+	}
+	if len(ep.HeaderParams) > 0 {
+		panes = append(panes, paneHeader)
+	}
+	if ep.Body != nil {
+		panes = append(panes, paneBody)
+	}
+	if len(panes) == 0 {
+		panes = []focusPane{panePath}
+	}
+	return panes
+}
+
+func (a *App) ensureValidPane() {
 	// if current pane doesn't exist, switch to first valid one
-	switch a.pane {
-	case panePath:
-		if !hasPath {
-			if hasQuery {
-				a.pane = paneQuery
-			} else if hasBody {
-				a.pane = paneBody
-			}
-		}
-	case paneQuery:
-		if !hasQuery {
-			if hasPath {
-				a.pane = panePath
-			} else if hasBody {
-				a.pane = paneBody
-			}
-		}
-	case paneBody:
-		if !hasBody {
-			if hasPath {
-				a.pane = panePath
-			} else if hasQuery {
-				a.pane = paneQuery
-			}
+	panes := a.builderPanes()
+	for _, p := range panes {
+		if p == a.pane {
+			return
 		}
 	}
+	a.pane = panes[0]
 }
 
 func (a *App) updatePanelColors() {
@@ -517,6 +1052,7 @@ func (a *App) updatePanelColors() {
 	}{
 		{"path", panePath},
 		{"query", paneQuery},
+		{"header", paneHeader},
 		{"body", paneBody},
 	}
 
@@ -555,13 +1091,56 @@ func (a *App) layoutResponse(maxX, maxY int) error {
 	return nil
 }
 
+func (a *App) layoutInspect(maxX, maxY int) error {
+	a.clearMainViews([]string{"inspect"})
+
+	if v, err := a.g.SetView("inspect", 0, 2, maxX-1, maxY-3); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Spec: " + a.activeEndpoint.Method + " " + a.activeEndpoint.Path
+		v.Wrap = false
+		v.Autoscroll = false
+		a.renderInspect(v)
+	}
+	if _, err := a.g.SetCurrentView("inspect"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *App) renderInspect(v *gocui.View) {
+	v.Clear()
+	op := openapi.Operation(a.activeEndpoint)
+	if op == nil {
+		fmt.Fprintln(v, "(operation not available)")
+		return
+	}
+	raw, err := json.Marshal(op)
+	if err != nil {
+		fmt.Fprintf(v, "(cannot render operation: %v)\n", err)
+		return
+	}
+	fmt.Fprintln(v, httpclient.FormatBody("application/json", raw, httpclient.FormatOptions{}))
+}
+
+// openInspect shows the raw OpenAPI operation behind the active endpoint.
+func (a *App) openInspect(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	a.scr = screenInspect
+	a.errorMsg = ""
+	return nil
+}
+
 func (a *App) clearMainViews(keep []string) {
-	keepSet := map[string]bool{"header": true, "footer": true}
+	keepSet := map[string]bool{"title": true, "footer": true}
 	for _, k := range keep {
 		keepSet[k] = true
 	}
 
-	for _, n := range []string{"filter", "endpoints", "selected", "path", "query", "body", "edit", "response"} {
+	for _, n := range []string{"filter", "facets", "endpoints", "selected", "path", "query", "header", "body", "notes", "edit", "response", "inspect", "history", "compare-diff", "compare-left", "compare-right"} {
 		if keepSet[n] {
 			continue
 		}
@@ -584,6 +1163,10 @@ func (a *App) bindKeys() error {
 	if err := g.SetKeybinding("", 'A', gocui.ModNone, a.openAuth); err != nil {
 		return err
 	}
+	// reload the spec from anywhere
+	if err := g.SetKeybinding("", gocui.KeyF5, gocui.ModNone, a.reloadSpec); err != nil {
+		return err
+	}
 
 	// spec url (handled by the prompt's custom Editor)
 
@@ -603,6 +1186,39 @@ func (a *App) bindKeys() error {
 	if err := g.SetKeybinding("endpoints", gocui.KeyBackspace2, gocui.ModNone, a.filterBackspace); err != nil {
 		return err
 	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlP, gocui.ModNone, a.togglePin); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyArrowLeft, gocui.ModNone, a.moveFacet(-1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyArrowRight, gocui.ModNone, a.moveFacet(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlT, gocui.ModNone, a.toggleFacet); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlG, gocui.ModNone, a.toggleGrouped); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlD, gocui.ModNone, a.toggleDeprecated); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlB, gocui.ModNone, a.toggleBadges); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlU, gocui.ModNone, a.promptCurlImport); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlO, gocui.ModNone, a.openSpecSwitcher); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlS, gocui.ModNone, a.openServerPicker); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("endpoints", gocui.KeyCtrlX, gocui.ModNone, a.openPostmanExport); err != nil {
+		return err
+	}
 	// number shortcuts 1-5 for quick endpoint selection
 	for i := 1; i <= 5; i++ {
 		if err := g.SetKeybinding("endpoints", rune('0'+i), gocui.ModNone, a.selectEndpointByNumber(i)); err != nil {
@@ -626,6 +1242,12 @@ func (a *App) bindKeys() error {
 	if err := g.SetKeybinding("query", gocui.KeyArrowUp, gocui.ModNone, a.moveRow("query", -1)); err != nil {
 		return err
 	}
+	if err := g.SetKeybinding("header", gocui.KeyArrowDown, gocui.ModNone, a.moveRow("header", 1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("header", gocui.KeyArrowUp, gocui.ModNone, a.moveRow("header", -1)); err != nil {
+		return err
+	}
 	if err := g.SetKeybinding("body", gocui.KeyArrowDown, gocui.ModNone, a.moveRow("body", 1)); err != nil {
 		return err
 	}
@@ -638,6 +1260,9 @@ func (a *App) bindKeys() error {
 	if err := g.SetKeybinding("query", gocui.KeyEnter, gocui.ModNone, a.beginEdit("query")); err != nil {
 		return err
 	}
+	if err := g.SetKeybinding("header", gocui.KeyEnter, gocui.ModNone, a.beginEdit("header")); err != nil {
+		return err
+	}
 	if err := g.SetKeybinding("body", gocui.KeyEnter, gocui.ModNone, a.bodyEnter); err != nil {
 		return err
 	}
@@ -647,12 +1272,62 @@ func (a *App) bindKeys() error {
 	if err := g.SetKeybinding("query", 'd', gocui.ModNone, a.resetParam); err != nil {
 		return err
 	}
+	if err := g.SetKeybinding("header", 'd', gocui.ModNone, a.resetParam); err != nil {
+		return err
+	}
 	if err := g.SetKeybinding("body", 'd', gocui.ModNone, a.resetParam); err != nil {
 		return err
 	}
+	if err := g.SetKeybinding("body", gocui.KeySpace, gocui.ModNone, a.toggleBodyInclude); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("body", 'v', gocui.ModNone, a.toggleBodySchema); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("query", 'f', gocui.ModNone, a.toggleQueryFlag); err != nil {
+		return err
+	}
 	if err := g.SetKeybinding("", gocui.KeyCtrlR, gocui.ModNone, a.executeRequest); err != nil {
 		return err
 	}
+	for _, name := range []string{"path", "query", "header", "body"} {
+		if err := g.SetKeybinding(name, 'y', gocui.ModNone, a.yankURL); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'u', gocui.ModNone, a.copyAsCurl); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'o', gocui.ModNone, a.openEndpointInBrowser); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'e', gocui.ModNone, a.applyExamplePreset); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 't', gocui.ModNone, a.toggleRawPath); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'z', gocui.ModNone, a.toggleMaximize); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'h', gocui.ModNone, a.openHeaderPreview); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'm', gocui.ModNone, a.editNotes); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'n', gocui.ModNone, a.toggleSkipAuth); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'i', gocui.ModNone, a.openInspect); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 's', gocui.ModNone, a.promptSavePreset); err != nil {
+			return err
+		}
+		if err := g.SetKeybinding(name, 'p', gocui.ModNone, a.openPresets); err != nil {
+			return err
+		}
+	}
 
 	// edit modal
 	if err := g.SetKeybinding("edit", gocui.KeyEnter, gocui.ModNone, a.confirmEdit); err != nil {
@@ -672,6 +1347,108 @@ func (a *App) bindKeys() error {
 	if err := g.SetKeybinding("response", gocui.KeyEnter, gocui.ModNone, a.responseToEndpoints); err != nil {
 		return err
 	}
+	if err := g.SetKeybinding("response", 'y', gocui.ModNone, a.yankURL); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'u', gocui.ModNone, a.copyAsCurl); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'd', gocui.ModNone, a.toggleDiff); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'c', gocui.ModNone, a.yankResponseBody); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 's', gocui.ModNone, a.saveResponseBody); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'x', gocui.ModNone, a.expandResponse); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", '<', gocui.ModNone, a.adjustJSONDepth(-1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", '>', gocui.ModNone, a.adjustJSONDepth(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", '[', gocui.ModNone, a.adjustJSONMaxItems(-1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", ']', gocui.ModNone, a.adjustJSONMaxItems(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", '/', gocui.ModNone, a.openResponseSearch); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'n', gocui.ModNone, a.nextMatch(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'N', gocui.ModNone, a.nextMatch(-1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'z', gocui.ModNone, a.toggleCompactJSON); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("response", 'k', gocui.ModNone, a.toggleSortKeys); err != nil {
+		return err
+	}
+
+	// picker modal
+	if err := g.SetKeybinding("picker", gocui.KeyArrowDown, gocui.ModNone, a.movePicker(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("picker", gocui.KeyArrowUp, gocui.ModNone, a.movePicker(-1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("picker", gocui.KeyEnter, gocui.ModNone, a.confirmPicker); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("picker", gocui.KeyCtrlD, gocui.ModNone, a.deletePickerItem); err != nil {
+		return err
+	}
+
+	// history
+	if err := g.SetKeybinding("", gocui.KeyCtrlL, gocui.ModNone, a.openHistory); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("history", gocui.KeyArrowDown, gocui.ModNone, a.moveHistorySel(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("history", gocui.KeyArrowUp, gocui.ModNone, a.moveHistorySel(-1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("history", gocui.KeyEnter, gocui.ModNone, a.reopenHistory); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("history", 'r', gocui.ModNone, a.rerunHistory); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("history", 'h', gocui.ModNone, a.exportSelectedHAR); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("history", 'H', gocui.ModNone, a.exportHAR); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("history", 'c', gocui.ModNone, a.markCompare); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("compare-left", gocui.KeyArrowDown, gocui.ModNone, a.scrollCompare(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("compare-left", gocui.KeyArrowUp, gocui.ModNone, a.scrollCompare(-1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("compare-left", 'r', gocui.ModNone, a.rerunCompare); err != nil {
+		return err
+	}
+
+	// inspect spec
+	if err := g.SetKeybinding("inspect", gocui.KeyArrowDown, gocui.ModNone, a.scrollView(1)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("inspect", gocui.KeyArrowUp, gocui.ModNone, a.scrollView(-1)); err != nil {
+		return err
+	}
 
 	// global typing for endpoint filter (bind printable ASCII)
 	for r := rune(32); r <= rune(126); r++ {
@@ -690,6 +1467,9 @@ func (a *App) bindKeys() error {
 	if err := g.SetKeybinding("auth-schemes", gocui.KeyEnter, gocui.ModNone, a.startAuthEdit); err != nil {
 		return err
 	}
+	if err := g.SetKeybinding("auth-schemes", gocui.KeyCtrlP, gocui.ModNone, a.openAuthProfiles); err != nil {
+		return err
+	}
 	if err := g.SetKeybinding("auth-form", gocui.KeyEnter, gocui.ModNone, a.submitAuth); err != nil {
 		return err
 	}
@@ -716,21 +1496,45 @@ func (a *App) bindKeys() error {
 	return nil
 }
 
-func (a *App) quit(*gocui.Gui, *gocui.View) error { return gocui.ErrQuit }
+func (a *App) quit(_ *gocui.Gui, v *gocui.View) error {
+	if typeInto(v, 'q') {
+		return nil
+	}
+	return gocui.ErrQuit
+}
 
 func (a *App) back(*gocui.Gui, *gocui.View) error {
-	if a.authOpen {
-		a.closeAuth()
+	if a.picker != nil {
+		a.closePicker()
 		return nil
 	}
 	if a.editing {
-		return a.closeEdit()
+		previewing := strings.HasPrefix(a.editTarget, "sendheader:")
+		if err := a.closeEdit(); err != nil {
+			return err
+		}
+		if previewing {
+			a.showHeaderPreview()
+		}
+		return nil
+	}
+	if a.authOpen {
+		a.closeAuth()
+		return nil
 	}
 	switch a.scr {
-	case screenResponse:
+	case screenResponse, screenInspect:
 		a.scr = screenBuilder
+		if a.activeEndpoint.Method == "" {
+			// an ad-hoc request (e.g. imported curl) has no builder
+			a.scr = screenEndpoints
+		}
 	case screenBuilder:
 		a.scr = screenEndpoints
+	case screenHistory:
+		a.scr = a.historyFrom
+	case screenCompare:
+		a.scr = screenHistory
 	case screenEndpoints:
 		// no previous screen
 	}
@@ -738,7 +1542,10 @@ func (a *App) back(*gocui.Gui, *gocui.View) error {
 	return nil
 }
 
-func (a *App) openAuth(*gocui.Gui, *gocui.View) error {
+func (a *App) openAuth(_ *gocui.Gui, v *gocui.View) error {
+	if typeInto(v, 'A') {
+		return nil
+	}
 	// If the modal is already open, don't reset state.
 	// This also prevents the global hotkey from clobbering input inside the modal.
 	if a.authOpen {
@@ -836,6 +1643,8 @@ func (a *App) startAuthEdit(*gocui.Gui, *gocui.View) error {
 	a.authMode = authModeToken
 	if scheme := a.secSchemes[a.authActiveName]; scheme.Type == "oauth2" && scheme.TokenURL != "" {
 		a.authMode = authModeUser
+	} else if scheme.Type == "apiKey" {
+		a.authMode = authModeApiKey
 	}
 	a.renderAuth()
 	return nil
@@ -849,7 +1658,7 @@ func (a *App) authTypeRune(r rune) func(*gocui.Gui, *gocui.View) error {
 			return nil
 		}
 		switch a.authMode {
-		case authModeToken:
+		case authModeToken, authModeApiKey:
 			a.authToken += string(r)
 		case authModeUser:
 			a.authUsername += string(r)
@@ -868,7 +1677,7 @@ func (a *App) authBackspace(*gocui.Gui, *gocui.View) error {
 		return nil
 	}
 	switch a.authMode {
-	case authModeToken:
+	case authModeToken, authModeApiKey:
 		if len(a.authToken) > 0 {
 			a.authToken = a.authToken[:len(a.authToken)-1]
 		}
@@ -893,10 +1702,9 @@ func (a *App) authNextField(*gocui.Gui, *gocui.View) error {
 	if !a.authOpen || !a.authEditing {
 		return nil
 	}
-	// token-only mode stays on token
+	// single-value modes stay where they are
 	scheme := a.secSchemes[a.authActiveName]
 	if scheme.Type != "oauth2" || scheme.TokenURL == "" {
-		a.authMode = authModeToken
 		a.renderAuth()
 		return nil
 	}
@@ -921,6 +1729,7 @@ func (a *App) clearAuth(*gocui.Gui, *gocui.View) error {
 	}
 	name := a.authActiveName
 	delete(a.authStore, name)
+	a.saveAuthTokens()
 	a.authToken = ""
 	a.authUsername = ""
 	a.authPassword = ""
@@ -945,37 +1754,45 @@ func (a *App) submitAuth(*gocui.Gui, *gocui.View) error {
 		tok := strings.TrimSpace(a.authToken)
 		if tok == "" {
 			delete(a.authStore, name)
+			a.saveAuthTokens()
 			a.authEditing = false
 			a.renderAuth()
 			return nil
 		}
 		a.authStore[name] = authState{schemeName: name, tokenType: "Bearer", token: tok, acquiredAt: time.Now()}
+		a.saveAuthTokens()
 		a.authEditing = false
 		a.authError = ""
+		a.notify("token saved for " + name)
 		a.renderAuth()
 		return nil
 	}
 
-	// OAuth2 password flow
-	if ss.Type == "oauth2" && ss.TokenURL != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		accessToken, tokenType, err := httpclient.FetchOAuthPasswordToken(ctx, a.baseURL, ss.TokenURL, a.authUsername, a.authPassword, a.authScope)
-		if err != nil {
-			a.authError = err.Error()
+	// API key manual entry
+	if ss.Type == "apiKey" {
+		key := strings.TrimSpace(a.authToken)
+		if key == "" {
+			delete(a.authStore, name)
+			a.saveAuthTokens()
+			a.authEditing = false
 			a.renderAuth()
 			return nil
 		}
-		if tokenType == "" {
-			tokenType = "Bearer"
-		}
-		a.authStore[name] = authState{schemeName: name, tokenType: tokenType, token: accessToken, acquiredAt: time.Now()}
+		a.authStore[name] = authState{schemeName: name, token: key, acquiredAt: time.Now()}
+		a.saveAuthTokens()
 		a.authEditing = false
 		a.authError = ""
+		a.notify("api key saved for " + name)
 		a.renderAuth()
 		return nil
 	}
 
+	// OAuth2 password flow
+	if ss.Type == "oauth2" && ss.TokenURL != "" {
+		a.fetchToken(name, ss.TokenURL)
+		return nil
+	}
+
 	a.authError = "unsupported security scheme"
 	a.renderAuth()
 	return nil
@@ -1002,14 +1819,24 @@ func (a *App) loadAuthFormFromStore() {
 }
 
 func (a *App) renderAuth() {
+	if v, err := a.g.View("auth-box"); err == nil {
+		v.Title = "Authentication"
+		if a.authProfile != "" && a.authProfile != defaultAuthProfile {
+			v.Title += " (profile: " + a.authProfile + ")"
+		}
+	}
+
 	// schemes list
 	if v, err := a.g.View("auth-schemes"); err == nil {
 		v.Clear()
 		for i, name := range a.authSchemes {
 			ss := a.secSchemes[name]
 			status := "[unset]"
-			if _, ok := a.authStore[name]; ok {
+			if st, ok := a.authStore[name]; ok {
 				status = "[set]"
+				if ss.Type == "oauth2" && tokenStale(st) {
+					status = "[stale]"
+				}
 			}
 			desc := strings.TrimSpace(ss.Description)
 			if desc != "" {
@@ -1033,12 +1860,19 @@ func (a *App) renderAuth() {
 			return
 		}
 
-		if a.authError != "" {
-			fmt.Fprintf(v, "error: %s\n\n", a.authError)
+		if !a.tokenFetchStarted.IsZero() {
+			elapsed := time.Since(a.tokenFetchStarted).Truncate(100 * time.Millisecond)
+			fmt.Fprintf(v, "%sfetching token... %s%s\n\n", colorYellow, elapsed, colorReset)
+		} else if a.authError != "" {
+			fmt.Fprintf(v, "%serror: %s%s\n\n", colorRed, a.authError, colorReset)
 		}
 
 		fmt.Fprintf(v, "scheme: %s\n", name)
-		fmt.Fprintf(v, "type:   %s\n\n", ss.Type)
+		fmt.Fprintf(v, "type:   %s\n", ss.Type)
+		if st, ok := a.authStore[name]; ok && !st.acquiredAt.IsZero() {
+			fmt.Fprintf(v, "set:    %s ago\n", shortAge(time.Since(st.acquiredAt)))
+		}
+		fmt.Fprintln(v)
 
 		if ss.Type == "http" && strings.EqualFold(ss.Scheme, "bearer") {
 			fmt.Fprintln(v, "Bearer token:")
@@ -1047,6 +1881,13 @@ func (a *App) renderAuth() {
 			return
 		}
 
+		if ss.Type == "apiKey" {
+			fmt.Fprintf(v, "API key (sent in %s %q):\n", ss.In, ss.ParamName)
+			fmt.Fprintf(v, "%s\n\n", a.authToken)
+			fmt.Fprintln(v, "enter: save   ctrl+d: clear   esc: close")
+			return
+		}
+
 		if ss.Type == "oauth2" {
 			if strings.TrimSpace(ss.TokenURL) == "" {
 				fmt.Fprintln(v, "OAuth2 scheme detected but no password-flow tokenUrl found in the spec.")
@@ -1081,32 +1922,248 @@ func mask(s string) string {
 }
 
 func (a *App) loadEndpoints() error {
+	apply, err := fetchEndpoints(a.specOptions(), a.specURL, a.specOverride, a.postmanFile)
+	if err != nil {
+		return err
+	}
+	apply(a)
+	return nil
+}
+
+// fetchEndpoints loads a spec, or a Postman collection when postmanFile is
+// set, and returns what applies it to the app. Only the returned function
+// touches App state, so the load can run off the UI goroutine.
+func fetchEndpoints(opts openapi.Options, specURL, override, postmanFile string) (func(*App), error) {
+	if postmanFile != "" {
+		imp, err := postman.LoadFile(postmanFile)
+		if err != nil {
+			return nil, err
+		}
+		return func(a *App) { a.applyPostman(imp) }, nil
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	doc, err := openapi.Load(ctx, a.specURL)
+	// a pasted /docs URL is tried at the spec's usual places; the one that
+	// loads keys config and resolves base URLs as the spec's own
+	var doc *openapi3.T
+	var err error
+	for i, u := range openapi.DocsSpecURLs(specURL) {
+		d, lerr := openapi.LoadWithOverride(ctx, opts, u, override)
+		if lerr == nil {
+			doc, specURL, err = d, u, nil
+			break
+		}
+		if i == 0 {
+			err = lerr
+		}
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	a.endpoints = openapi.ExtractEndpoints(doc)
-	a.secSchemes = openapi.ExtractSecuritySchemes(doc)
+	cachedAt, offline := openapi.FromCache(specURL)
+	return func(a *App) {
+		a.specURL = specURL
+		if offline {
+			a.errorMsg = fmt.Sprintf("offline: using the copy of %s cached %s", a.specURL, cachedAt.Format("2006-01-02 15:04"))
+		}
+		if doc.Info != nil {
+			a.specTitle = strings.TrimSpace(doc.Info.Title)
+		}
+		a.endpoints = openapi.ExtractEndpoints(doc)
+		a.secSchemes = openapi.ExtractSecuritySchemes(doc)
+		a.servers = extractServers(doc)
+		// --base-url wins, then the spec's first server, then the directory the
+		// spec was fetched from.
+		if a.baseURL == "" {
+			a.baseURL = baseURLFromOpenAPI(doc, a.specURL)
+		}
+		if a.baseURL == "" && isHTTPURL(a.specURL) {
+			a.baseURL = baseURLFromURLSpec(a.specURL)
+		}
+		a.buildFacets()
+		a.filter = ""
+		a.selected = 0
+		a.recomputeFilter()
+	}, nil
+}
+
+// applyPostman uses the endpoints of a Postman collection. Postman has no
+// security schemes, so auth comes from the collection's own headers.
+func (a *App) applyPostman(imp *postman.Imported) {
+	if a.specURL == "" {
+		// also keys per-spec config (pins, presets)
+		a.specURL = "postman:" + a.postmanFile
+	}
+	a.specTitle = imp.Name
+	a.endpoints = imp.Endpoints
+	a.secSchemes = map[string]model.SecurityScheme{}
+	a.servers = nil
 	if a.baseURL == "" {
-		a.baseURL = baseURLFromOpenAPI(doc)
+		a.baseURL = imp.BaseURL
+	}
+	for _, skip := range imp.Skipped {
+		debugLog.Printf("postman: skipped %s", skip)
+	}
+	if n := len(imp.Skipped); n > 0 {
+		a.notify(fmt.Sprintf("skipped %d of %d requests (%s)", n, n+len(imp.Endpoints), imp.Skipped[0]))
 	}
+	a.buildFacets()
 	a.filter = ""
 	a.selected = 0
 	a.recomputeFilter()
+}
+
+// buildFacets collects the methods (in conventional order) and tags present
+// in the spec.
+func (a *App) buildFacets() {
+	methods := map[string]bool{}
+	tags := map[string]bool{}
+	for _, ep := range a.endpoints {
+		methods[ep.Method] = true
+		for _, t := range ep.Tags {
+			tags[t] = true
+		}
+	}
+	a.facets = a.facets[:0]
+	for _, m := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+		if methods[m] {
+			a.facets = append(a.facets, facet{kind: facetMethod, value: m})
+		}
+	}
+	sortedTags := make([]string, 0, len(tags))
+	for t := range tags {
+		sortedTags = append(sortedTags, t)
+	}
+	sort.Strings(sortedTags)
+	for _, t := range sortedTags {
+		a.facets = append(a.facets, facet{kind: facetTag, value: t})
+	}
+	a.facetCursor = 0
+	a.activeFacets = map[facet]bool{}
+}
+
+// listable reports whether ep belongs in the endpoint list before the text
+// filter: it matches the facets and isn't a hidden deprecated operation.
+func (a *App) listable(ep model.Endpoint) bool {
+	return a.matchesFacets(ep) && !(a.hideDeprecated && ep.Deprecated)
+}
+
+// matchesFacets applies the active chips: any selected method AND any
+// selected tag (an empty group matches everything).
+func (a *App) matchesFacets(ep model.Endpoint) bool {
+	var wantMethod, wantTag, okMethod, okTag bool
+	for f := range a.activeFacets {
+		switch f.kind {
+		case facetMethod:
+			wantMethod = true
+			if ep.Method == f.value {
+				okMethod = true
+			}
+		case facetTag:
+			wantTag = true
+			for _, t := range ep.Tags {
+				if t == f.value {
+					okTag = true
+				}
+			}
+		}
+	}
+	return (!wantMethod || okMethod) && (!wantTag || okTag)
+}
+
+func (a *App) moveFacet(delta int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if a.scr != screenEndpoints || len(a.facets) == 0 {
+			return nil
+		}
+		a.facetCursor += delta
+		if a.facetCursor < 0 {
+			a.facetCursor = 0
+		}
+		if a.facetCursor >= len(a.facets) {
+			a.facetCursor = len(a.facets) - 1
+		}
+		a.renderFacets()
+		return nil
+	}
+}
+
+func (a *App) toggleFacet(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints || len(a.facets) == 0 {
+		return nil
+	}
+	f := a.facets[a.facetCursor]
+	if a.activeFacets[f] {
+		delete(a.activeFacets, f)
+	} else {
+		a.activeFacets[f] = true
+	}
+	a.recomputeFilter()
+	a.renderFacets()
+	a.renderEndpoints()
 	return nil
 }
 
+func (a *App) renderFacets() {
+	v, err := a.g.View("facets")
+	if err != nil {
+		return
+	}
+	v.Clear()
+	for i, f := range a.facets {
+		if i > 0 && f.kind == facetTag && a.facets[i-1].kind == facetMethod {
+			fmt.Fprint(v, colorDim+"| "+colorReset)
+		}
+		label := f.value
+		if f.kind == facetTag {
+			label = "#" + label
+		}
+		color := colorDim
+		if a.activeFacets[f] {
+			color = colorGreen
+		}
+		if i == a.facetCursor {
+			label = "[" + label + "]"
+		} else {
+			label = " " + label + " "
+		}
+		fmt.Fprint(v, color+label+colorReset+" ")
+	}
+}
+
 func normalizeBaseURL(in string) string {
 	in = strings.TrimSpace(in)
 	if in == "" {
 		return ""
 	}
-	if strings.HasPrefix(in, "http://") || strings.HasPrefix(in, "https://") {
-		return in
+	lower := strings.ToLower(in)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		scheme, rest, _ := strings.Cut(in, "://")
+		return scheme + "://" + bracketIPv6Host(rest)
+	}
+	return "http://" + bracketIPv6Host(in)
+}
+
+// bracketIPv6Host fixes up the host of a scheme-less URL ("host[:port][/path]")
+// so url.Parse accepts it: a bare IPv6 literal ("::1", "fe80::1%eth0") is
+// wrapped in brackets, and a zone's "%" is escaped as "%25". Bracketed hosts
+// with ports ("[::1]:8080") and plain hosts ("localhost:3000") pass through.
+func bracketIPv6Host(in string) string {
+	host, rest := in, ""
+	if i := strings.Index(in, "/"); i >= 0 {
+		host, rest = in[:i], in[i:]
+	}
+	if !strings.HasPrefix(host, "[") {
+		addr, _, _ := strings.Cut(host, "%")
+		if !strings.Contains(addr, ":") || net.ParseIP(addr) == nil {
+			return in
+		}
+		host = "[" + host + "]"
+	}
+	if !strings.Contains(host, "%25") {
+		host = strings.Replace(host, "%", "%25", 1)
 	}
-	return "http://" + in
+	return host + rest
 }
 
 func baseURLFromURLSpec(specURL string) string {
@@ -1127,18 +2184,46 @@ func baseURLFromURLSpec(specURL string) string {
 	return strings.TrimRight(u.String(), "/")
 }
 
-func baseURLFromOpenAPI(doc *openapi3.T) string {
-	if doc == nil {
-		return ""
-	}
-	if len(doc.Servers) == 0 {
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// baseURLFromOpenAPI returns the first server's URL, path prefix included
+// ("https://api.example.com/v2") and {variables} set to their defaults.
+func baseURLFromOpenAPI(doc *openapi3.T, specURL string) string {
+	servers := extractServers(doc)
+	if len(servers) == 0 {
 		return ""
 	}
-	u := strings.TrimSpace(doc.Servers[0].URL)
-	// For now, we only support concrete URLs (no {vars}).
+	return resolveServerURL(servers[0].expand(nil), specURL)
+}
+
+// resolveServerURL turns a server URL into a base URL. A relative server URL
+// ("/v2") is resolved against the spec's own URL, as OpenAPI prescribes; with
+// a local spec file there's nothing to resolve against, so it yields "".
+func resolveServerURL(u, specURL string) string {
+	u = strings.TrimSpace(u)
 	if u == "" || strings.Contains(u, "{") {
 		return ""
 	}
+	if strings.HasPrefix(u, "/") || strings.HasPrefix(u, ".") {
+		if !isHTTPURL(specURL) {
+			return ""
+		}
+		spec, err := url.Parse(specURL)
+		if err != nil {
+			return ""
+		}
+		ref, err := url.Parse(u)
+		if err != nil {
+			return ""
+		}
+		u = spec.ResolveReference(ref).String()
+	} else if !strings.Contains(u, "://") {
+		// "localhost:3000" would parse as scheme "localhost"; give scheme-less
+		// hosts (including IPv6 literals) the same treatment as XHARK_BASE_URL.
+		u = normalizeBaseURL(u)
+	}
 	if p, err := url.Parse(u); err == nil {
 		p.Fragment = ""
 		p.RawQuery = ""
@@ -1191,15 +2276,15 @@ func (a *App) moveSel(delta int) func(*gocui.Gui, *gocui.View) error {
 		if a.scr != screenEndpoints {
 			return nil
 		}
-		if len(a.filtered) == 0 {
+		if a.listLen() == 0 {
 			return nil
 		}
 		a.selected += delta
 		if a.selected < 0 {
 			a.selected = 0
 		}
-		if a.selected >= len(a.filtered) {
-			a.selected = len(a.filtered) - 1
+		if a.selected >= a.listLen() {
+			a.selected = a.listLen() - 1
 		}
 		if ev, err := a.g.View("endpoints"); err == nil {
 			ev.SetCursor(0, a.selected)
@@ -1208,36 +2293,187 @@ func (a *App) moveSel(delta int) func(*gocui.Gui, *gocui.View) error {
 	}
 }
 
-func (a *App) openBuilder(*gocui.Gui, *gocui.View) error {
+// endpointKey identifies an endpoint within a spec for persisted state.
+func endpointKey(ep model.Endpoint) string {
+	if ep.Service != "" {
+		return ep.Service + " " + ep.Method + " " + ep.Path
+	}
+	return ep.Method + " " + ep.Path
+}
+
+func (a *App) specConfig() *config.SpecConfig {
+	if a.cfg == nil {
+		a.cfg = &config.Config{}
+	}
+	return a.cfg.Spec(a.specURL)
+}
+
+// togglePin pins/unpins the selected endpoint and persists the change.
+func (a *App) togglePin(*gocui.Gui, *gocui.View) error {
 	if a.scr != screenEndpoints {
 		return nil
 	}
-	if len(a.filtered) == 0 {
+	cur, ok := a.selectedEndpoint()
+	if !ok {
 		return nil
 	}
-	idx := a.filtered[a.selected]
-	a.activeEndpoint = a.endpoints[idx]
-	a.pathVals = map[string]string{}
-	a.queryVals = map[string]string{}
-	a.bodyVals = map[string]string{}
-	a.bodyRaw = ""
-	a.pane = panePath
-	a.scr = screenBuilder
-	a.errorMsg = ""
-	return nil
-}
-
-func (a *App) selectEndpointByNumber(num int) func(*gocui.Gui, *gocui.View) error {
-	return func(g *gocui.Gui, v *gocui.View) error {
-		if a.scr != screenEndpoints {
-			return nil
-		}
-		idx := num - 1 // convert 1-based to 0-based
-		if idx < 0 || idx >= len(a.filtered) {
-			return nil
+	ep := a.endpoints[cur]
+	pinned := a.specConfig().TogglePin(endpointKey(ep))
+	if err := a.cfg.Save(); err != nil {
+		a.errorMsg = "save config: " + err.Error()
+	}
+	a.recomputeFilter()
+	// keep the cursor on the endpoint we just (un)pinned
+	a.selectEndpointIdx(cur)
+	a.renderEndpoints()
+	if a.errorMsg == "" {
+		if pinned {
+			a.notify("pinned " + endpointKey(ep))
+		} else {
+			a.notify("unpinned " + endpointKey(ep))
 		}
-		a.selected = idx
-		return a.openBuilder(g, v)
+	}
+	return nil
+}
+
+// openPostmanExport asks which endpoints to export as a Postman collection.
+func (a *App) openPostmanExport(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints || len(a.endpoints) == 0 {
+		return nil
+	}
+	scopes := []string{"all endpoints", "pinned only", "with saved presets only"}
+	a.openPicker(&picker{
+		title: "Export Postman collection",
+		items: scopes,
+		onPick: func(i int) error {
+			a.exportPostman(i)
+			return nil
+		},
+	})
+	return nil
+}
+
+// exportPostman writes the endpoints in scope (0 all, 1 pinned, 2 with
+// presets) to a collection file in the working directory. Endpoints with
+// presets export one request per preset.
+func (a *App) exportPostman(scope int) {
+	sc := a.specConfig()
+	var items []postman.ExportItem
+	for _, ep := range a.endpoints {
+		presets := sc.Presets[operationKey(ep)]
+		switch {
+		case scope == 1 && !sc.IsPinned(endpointKey(ep)):
+			continue
+		case scope == 2 && len(presets) == 0:
+			continue
+		}
+		name := firstNonEmpty(ep.Summary, endpointKey(ep))
+		if len(presets) == 0 {
+			items = append(items, postman.ExportItem{Name: name, Endpoint: ep, Body: exampleBody(ep)})
+			continue
+		}
+		for _, p := range presets {
+			body := p.BodyRaw
+			if body == "" && len(p.BodyVals) > 0 {
+				if b, err := httpclient.BuildJSONBody(ep, p.BodyVals); err == nil && b != nil {
+					body = string(b)
+				}
+			}
+			if body == "" {
+				body = exampleBody(ep)
+			}
+			items = append(items, postman.ExportItem{
+				Name:       name + " (" + p.Name + ")",
+				Endpoint:   ep,
+				PathVals:   p.PathVals,
+				QueryVals:  p.QueryVals,
+				HeaderVals: p.HeaderVals,
+				Body:       body,
+			})
+		}
+	}
+	if len(items) == 0 {
+		a.errorMsg = "nothing to export"
+		return
+	}
+	title := firstNonEmpty(a.specTitle, "xhark")
+	// credentials in the base URL stay out of a file meant to be shared
+	baseURL := a.baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.User != nil {
+		u.User = nil
+		baseURL = u.String()
+	}
+	b, err := postman.Export(title, baseURL, items)
+	if err != nil {
+		a.errorMsg = "export: " + err.Error()
+		return
+	}
+	file := fileSlug(title) + ".postman_collection.json"
+	if err := os.WriteFile(file, b, 0o600); err != nil {
+		a.errorMsg = "export: " + err.Error()
+		return
+	}
+	a.errorMsg = ""
+	a.notify(fmt.Sprintf("exported %d requests to %s", len(items), file))
+}
+
+// fileSlug makes a title safe to use as a file name.
+func fileSlug(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+	if s == "" {
+		return "xhark"
+	}
+	return s
+}
+
+func (a *App) openBuilder(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints {
+		return nil
+	}
+	if a.grouped && a.selected < len(a.rows) && a.rows[a.selected].idx < 0 {
+		a.toggleGroup()
+		return nil
+	}
+	idx, ok := a.selectedEndpoint()
+	if !ok {
+		return nil
+	}
+	a.startBuilder(a.endpoints[idx])
+	return nil
+}
+
+// startBuilder opens the builder on ep with fresh values.
+func (a *App) startBuilder(ep model.Endpoint) {
+	a.activeEndpoint = ep
+	a.pathVals = map[string]string{}
+	a.queryVals = map[string]string{}
+	a.headerVals = map[string]string{}
+	a.bodyVals = map[string]string{}
+	a.bodyRaw = ""
+	if a.activeEndpoint.Body != nil && !a.activeEndpoint.Body.Text {
+		a.bodyRaw = firstNonEmpty(a.initialBody, a.activeEndpoint.Body.Default)
+	}
+	a.clearBodyDraft()
+	a.skipAuth = a.noAuth
+	a.maximized = false
+	a.pane = panePath
+	a.scr = screenBuilder
+	a.errorMsg = ""
+}
+
+func (a *App) selectEndpointByNumber(num int) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if a.scr != screenEndpoints {
+			return nil
+		}
+		row := a.nthEndpointRow(num - 1) // convert 1-based to 0-based
+		if row < 0 {
+			return nil
+		}
+		a.selected = row
+		return a.openBuilder(g, v)
 	}
 }
 
@@ -1255,39 +2491,15 @@ func (a *App) tabPane(*gocui.Gui, *gocui.View) error {
 		return nil
 	}
 
-	// get available panes
-	hasPath := len(a.activeEndpoint.PathParams) > 0
-	hasQuery := len(a.activeEndpoint.QueryParams) > 0
-	hasBody := a.activeEndpoint.Body != nil
-
 	// cycle to next available pane
-	for i := 0; i < 3; i++ {
-		switch a.pane {
-		case panePath:
-			a.pane = paneQuery
-		case paneQuery:
-			a.pane = paneBody
-		case paneBody:
-			a.pane = panePath
-		}
-
-		// check if new pane is valid
-		switch a.pane {
-		case panePath:
-			if hasPath {
-				goto done
-			}
-		case paneQuery:
-			if hasQuery {
-				goto done
-			}
-		case paneBody:
-			if hasBody {
-				goto done
-			}
+	panes := a.builderPanes()
+	next := panes[0]
+	for i, p := range panes {
+		if p == a.pane && i+1 < len(panes) {
+			next = panes[i+1]
 		}
 	}
-done:
+	a.pane = next
 	a.updatePanelColors()
 	a.setBuilderFocus()
 	return nil
@@ -1297,14 +2509,9 @@ func (a *App) setBuilderFocus() {
 	if a.scr != screenBuilder || a.editing {
 		return
 	}
-	name := "path"
-	switch a.pane {
-	case panePath:
+	name := paneNames[a.pane]
+	if name == "" {
 		name = "path"
-	case paneQuery:
-		name = "query"
-	case paneBody:
-		name = "body"
 	}
 	a.g.SetCurrentView(name)
 }
@@ -1347,7 +2554,12 @@ func (a *App) resetParam(*gocui.Gui, *gocui.View) error {
 		paneName = "path"
 	case paneQuery:
 		paneName = "query"
+	case paneHeader:
+		paneName = "header"
 	case paneBody:
+		if a.bodySchemaView {
+			return nil
+		}
 		paneName = "body"
 	}
 	if paneName == "" {
@@ -1366,10 +2578,83 @@ func (a *App) resetParam(*gocui.Gui, *gocui.View) error {
 		delete(a.pathVals, key)
 	case paneQuery:
 		delete(a.queryVals, key)
+	case paneHeader:
+		delete(a.headerVals, key)
 	case paneBody:
 		delete(a.bodyVals, key)
 		a.bodyRaw = ""
+		a.clearBodyDraft()
+	}
+	a.renderBuilder()
+	return nil
+}
+
+// toggleBodyInclude marks the selected optional, empty body field to be sent
+// anyway (with its default, or as null) instead of being left out, or back.
+func (a *App) toggleBodyInclude(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing || a.pane != paneBody || a.bodySchemaView {
+		return nil
+	}
+	v, err := a.g.View("body")
+	if err != nil {
+		return nil
+	}
+	key := a.selectedKey("body", v)
+	if key == "" || a.activeEndpoint.Body == nil {
+		return nil
+	}
+	for _, f := range a.activeEndpoint.Body.Fields {
+		if f.Name != key {
+			continue
+		}
+		if f.Required {
+			a.errorMsg = key + " is required; it's always sent"
+			return nil
+		}
+		val, set := a.bodyVals[key]
+		switch {
+		case val != "":
+			a.errorMsg = key + " has a value; it's sent (d to reset)"
+			return nil
+		case set:
+			delete(a.bodyVals, key)
+		default:
+			a.bodyVals[key] = ""
+		}
+		a.errorMsg = ""
+		a.renderBuilder()
+		return nil
+	}
+	return nil
+}
+
+// toggleQueryFlag switches the selected boolean query param between
+// "?key=true" and presence-only "?key".
+func (a *App) toggleQueryFlag(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing || a.pane != paneQuery {
+		return nil
 	}
+	v, err := a.g.View("query")
+	if err != nil {
+		return nil
+	}
+	key := a.selectedKey("query", v)
+	if key == "" {
+		return nil
+	}
+	// activeEndpoint shares its slices with a.endpoints; copy before mutating.
+	params := append([]model.Param(nil), a.activeEndpoint.QueryParams...)
+	for i := range params {
+		if params[i].Name != key {
+			continue
+		}
+		if params[i].Type != model.TypeBoolean {
+			a.errorMsg = "flag mode only applies to boolean params"
+			return nil
+		}
+		params[i].AllowEmptyValue = !params[i].AllowEmptyValue
+	}
+	a.activeEndpoint.QueryParams = params
 	a.renderBuilder()
 	return nil
 }
@@ -1381,7 +2666,16 @@ func (a *App) bodyEnter(g *gocui.Gui, v *gocui.View) error {
 	if a.activeEndpoint.Body == nil {
 		return nil
 	}
-	// Always drop into $EDITOR for JSON body editing.
+	if a.bodySchemaView {
+		a.errorMsg = "schema view is read-only (v to edit values)"
+		return nil
+	}
+	if a.noEditor {
+		if isTextBody(a.activeEndpoint) {
+			return a.openEditModal(g, "body (text/plain)", a.bodyRaw, "bodytext:raw")
+		}
+		return a.beginEdit("body")(g, v)
+	}
 	return a.editBodyInEditor(g, v)
 }
 
@@ -1393,9 +2687,25 @@ func (a *App) editBodyInEditor(*gocui.Gui, *gocui.View) error {
 		return nil
 	}
 
+	if isTextBody(a.activeEndpoint) {
+		return a.editTextBodyInEditor()
+	}
+
 	// Seed with existing raw JSON, otherwise generate a "swagger-like" starter
 	// object using defaults/examples when available.
-	seed := strings.TrimSpace(a.bodyRaw)
+	seed := ""
+	if strings.TrimSpace(a.bodyDraft) != "" {
+		seed = a.draftSeed()
+	}
+	if seed == "" {
+		seed = strings.TrimSpace(a.bodyRaw)
+	}
+	if seed == "" && a.activeEndpoint.Body != nil {
+		seed = a.activeEndpoint.Body.Example
+	}
+	if seed == "" && a.activeEndpoint.Body != nil && !a.activeEndpoint.Body.Supported {
+		seed = a.activeEndpoint.Body.Skeleton
+	}
 	if seed == "" {
 		obj := map[string]any{}
 		if a.activeEndpoint.Body != nil {
@@ -1444,7 +2754,99 @@ func (a *App) editBodyInEditor(*gocui.Gui, *gocui.View) error {
 	return gocui.ErrQuit
 }
 
+// editTextBodyInEditor opens a text/plain body in $EDITOR, seeded with the
+// current text or the spec's example.
+func (a *App) editTextBodyInEditor() error {
+	seed := firstNonEmpty(a.bodyRaw, a.activeEndpoint.Body.Example)
+	f, err := os.CreateTemp("", "xhark-body-*.txt")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	if _, err := f.WriteString(seed); err != nil {
+		return nil
+	}
+	a.suspendEditorFile = f.Name()
+	return gocui.ErrQuit
+}
+
+func isTextBody(ep model.Endpoint) bool {
+	return ep.Body != nil && ep.Body.Text
+}
+
+func isFormBody(ep model.Endpoint) bool {
+	return ep.Body != nil && ep.Body.Form
+}
+
+// applyExamplePreset fills every param and the body from the examples declared
+// in the spec, so a documented request can be fired with one key.
+func (a *App) applyExamplePreset(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	ep := a.activeEndpoint
+	filled := 0
+	for _, p := range ep.PathParams {
+		if p.Example != "" {
+			a.pathVals[p.Name] = p.Example
+			filled++
+		}
+	}
+	for _, p := range ep.QueryParams {
+		if p.Example != "" {
+			a.queryVals[p.Name] = p.Example
+			filled++
+		}
+	}
+	for _, p := range ep.HeaderParams {
+		if p.Example != "" {
+			a.headerVals[p.Name] = p.Example
+			filled++
+		}
+	}
+	if body := exampleBody(ep); body != "" {
+		a.bodyRaw = body
+		filled++
+	}
+	if filled == 0 {
+		a.errorMsg = "no examples declared in the spec for this endpoint"
+		return nil
+	}
+	a.errorMsg = ""
+	a.notify("filled from spec examples (ctrl+r to run)")
+	a.renderBuilder()
+	return nil
+}
+
+// exampleBody returns the spec's body example for ep, or one assembled from
+// per-field examples, as indented JSON ("" if there are none).
+func exampleBody(ep model.Endpoint) string {
+	if ep.Body == nil {
+		return ""
+	}
+	if ep.Body.Example != "" {
+		return ep.Body.Example
+	}
+	obj := map[string]any{}
+	for _, f := range ep.Body.Fields {
+		if f.Example != "" {
+			obj[f.Name] = coerceJSONScalar(f.Type, f.Example)
+		}
+	}
+	if len(obj) == 0 {
+		return ""
+	}
+	b, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
 func (a *App) runExternalEditor(file string) error {
+	notesFor := a.notesFor
+	a.notesFor = ""
+
 	editor := strings.TrimSpace(os.Getenv("XHARK_EDITOR"))
 	if editor == "" {
 		editor = strings.TrimSpace(os.Getenv("EDITOR"))
@@ -1471,31 +2873,113 @@ func (a *App) runExternalEditor(file string) error {
 		return err
 	}
 
-	raw := strings.TrimSpace(string(b))
+	if notesFor != "" {
+		a.saveNote(notesFor, string(b))
+		return nil
+	}
+
+	if isTextBody(a.activeEndpoint) {
+		// editors end files with a newline the user didn't mean to send
+		a.bodyRaw = strings.TrimSuffix(string(b), "\n")
+		return nil
+	}
+
+	raw := strings.TrimSpace(stripDraftMarkers(string(b)))
 	if raw == "" {
 		a.bodyRaw = ""
+		a.clearBodyDraft()
 		return nil
 	}
 
-	// Validate JSON and normalize it.
+	// Validate JSON and normalize it. On failure keep what the user typed so
+	// the next edit starts from it instead of from scratch.
+	parse := raw
+	if a.jsonc {
+		parse = stripJSONC(raw)
+	}
 	var v any
-	dec := json.NewDecoder(strings.NewReader(raw))
+	dec := json.NewDecoder(strings.NewReader(parse))
 	dec.UseNumber()
 	if err := dec.Decode(&v); err != nil {
-		return fmt.Errorf("invalid json body: %w", err)
+		a.bodyDraft = raw
+		a.bodyDraftErr = fmt.Sprintf("invalid json body%s: %v", jsonErrorLocation(parse, err), err)
+		return fmt.Errorf("%s (enter on body to fix)", a.bodyDraftErr)
 	}
 	// Reject trailing junk (second JSON value)
 	if dec.More() {
-		return fmt.Errorf("invalid json body: multiple json values")
+		a.bodyDraft = raw
+		line, col := lineCol(parse, dec.InputOffset())
+		a.bodyDraftErr = fmt.Sprintf("invalid json body at line %d, col %d: multiple json values", line, col)
+		return fmt.Errorf("%s (enter on body to fix)", a.bodyDraftErr)
 	}
 	norm, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
 	a.bodyRaw = string(norm)
+	a.clearBodyDraft()
 	return nil
 }
 
+// draftMarker prefixes the error comment we put after a broken draft;
+// they're stripped again when the editor returns.
+const draftMarker = "// xhark: "
+
+func (a *App) clearBodyDraft() {
+	a.bodyDraft = ""
+	a.bodyDraftErr = ""
+}
+
+// draftSeed returns the broken draft followed by the parse error as a
+// comment. It goes at the end so the reported line numbers still match.
+func (a *App) draftSeed() string {
+	return a.bodyDraft + "\n" + draftMarker + a.bodyDraftErr + "\n"
+}
+
+// stripDraftMarkers drops the error comment added by draftSeed.
+func stripDraftMarkers(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), strings.TrimSpace(draftMarker)) {
+			continue
+		}
+		out = append(out, l)
+	}
+	return strings.Join(out, "\n")
+}
+
+// jsonErrorLocation formats " at line L, col C" for decoder errors that carry
+// an offset, or "" if the error has none.
+func jsonErrorLocation(raw string, err error) string {
+	var offset int64
+	var syn *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syn):
+		offset = syn.Offset
+	case errors.As(err, &typ):
+		offset = typ.Offset
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		offset = int64(len(raw))
+	default:
+		return ""
+	}
+	line, col := lineCol(raw, offset)
+	return fmt.Sprintf(" at line %d, col %d", line, col)
+}
+
+// lineCol converts a byte offset into 1-based line and column numbers.
+func lineCol(s string, offset int64) (int, int) {
+	if offset > int64(len(s)) {
+		offset = int64(len(s))
+	}
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := int(offset) - strings.LastIndex(before, "\n")
+	return line, col
+}
+
 func splitCommand(s string) []string {
 	// Minimal shell-like splitting: whitespace, no quotes/escapes.
 	fields := strings.Fields(s)
@@ -1525,30 +3009,64 @@ func coerceJSONScalar(t model.ParamType, raw string) any {
 }
 
 func (a *App) authHeadersForEndpoint(ep model.Endpoint) map[string]string {
+	headers, _, ok := a.authForEndpoint(ep)
+	if !ok {
+		return nil
+	}
+	return headers
+}
+
+// authForEndpoint resolves the credentials we hold for ep into the headers
+// and query params to send. ok is false when no requirement is satisfied.
+func (a *App) authForEndpoint(ep model.Endpoint) (headers, query map[string]string, ok bool) {
 	// No security requirements: nothing to inject.
 	if len(ep.Security) == 0 {
-		return nil
+		return nil, nil, false
 	}
 
 	// Swagger semantics: SecurityRequirements is OR-of-requirements.
 	// Pick the first requirement that is fully satisfied by our authStore.
+	// Schemes within one are all applied, each to its own location, in name
+	// order so two that clash resolve the same way every time.
 	for _, req := range ep.Security {
-		ok := true
-		headers := map[string]string{}
+		satisfied := true
+		headers, query = map[string]string{}, map[string]string{}
+		var cookies []string
+		names := make([]string, 0, len(req))
 		for schemeName := range req {
+			names = append(names, schemeName)
+		}
+		sort.Strings(names)
+		for _, schemeName := range names {
 			st, has := a.authStore[schemeName]
 			if !has || strings.TrimSpace(st.token) == "" {
-				ok = false
+				satisfied = false
 				break
 			}
-			// MVP: only Bearer-ish schemes -> Authorization header.
-			headers["Authorization"] = strings.TrimSpace(st.tokenType) + " " + strings.TrimSpace(st.token)
+			token := strings.TrimSpace(st.token)
+			ss := a.secSchemes[schemeName]
+			if ss.Type != "apiKey" {
+				// MVP: only Bearer-ish schemes -> Authorization header.
+				httpclient.SetHeader(headers, "Authorization", strings.TrimSpace(st.tokenType)+" "+token)
+				continue
+			}
+			switch ss.In {
+			case "query":
+				query[ss.ParamName] = token
+			case "cookie":
+				cookies = append(cookies, ss.ParamName+"="+token)
+			default:
+				httpclient.SetHeader(headers, ss.ParamName, token)
+			}
 		}
-		if ok {
-			return headers
+		if satisfied {
+			if len(cookies) > 0 {
+				httpclient.SetHeader(headers, "Cookie", strings.Join(cookies, "; "))
+			}
+			return headers, query, true
 		}
 	}
-	return nil
+	return nil, nil, false
 }
 
 func (a *App) beginEdit(viewName string) func(*gocui.Gui, *gocui.View) error {
@@ -1564,42 +3082,92 @@ func (a *App) beginEdit(viewName string) func(*gocui.Gui, *gocui.View) error {
 		if key == "" {
 			return nil
 		}
-
-		a.editing = true
-		a.editTarget = viewName + ":" + key
-
-		// centered modal dialog
-		maxX, maxY := g.Size()
-		width := 60
-		if width > maxX-4 {
-			width = maxX - 4
+		if viewName == "query" {
+			for _, p := range a.activeEndpoint.QueryParams {
+				if p.Name == key && len(p.Enum) > 0 {
+					a.openEnumPicker(p)
+					return nil
+				}
+			}
 		}
-		height := 3
-		x0 := (maxX - width) / 2
-		y0 := (maxY - height) / 2
-		x1 := x0 + width
-		y1 := y0 + height
+		return a.openEditModal(g, key, a.currentValueFor(key, viewName), viewName+":"+key)
+	}
+}
 
-		if ev, err := g.SetView("edit", x0, y0, x1, y1); err != nil {
-			if err != gocui.ErrUnknownView {
-				return err
-			}
-			ev.Title = fmt.Sprintf(" %s (enter=ok, esc=cancel) ", key)
-			ev.Editable = true
-			ev.Editor = singleLineEditor{}
-			ev.BgColor = gocui.ColorBlack
-			ev.FgColor = gocui.ColorWhite
+// openEnumPicker offers an enum query param's values, with what each one
+// means when the spec says so. The first entry clears the param.
+func (a *App) openEnumPicker(p model.Param) {
+	items := []string{"(unset)"}
+	for _, e := range p.Enum {
+		item := e
+		if d := p.EnumDescriptions[e]; d != "" {
+			item += "  " + colorDim + d + colorReset
 		}
-		// always update content and focus (in case view already existed)
-		if ev, err := g.View("edit"); err == nil {
-			ev.Clear()
-			currentVal := a.currentValueFor(key, viewName)
-			fmt.Fprint(ev, currentVal)
-			ev.SetCursor(len(currentVal), 0)
+		items = append(items, item)
+	}
+	sel := 0
+	for i, e := range p.Enum {
+		if e == a.queryVals[p.Name] {
+			sel = i + 1
 		}
-		g.SetCurrentView("edit")
-		return nil
 	}
+	a.openPicker(&picker{
+		title: p.Name,
+		items: items,
+		sel:   sel,
+		onPick: func(i int) error {
+			if i == 0 {
+				delete(a.queryVals, p.Name)
+			} else {
+				a.queryVals[p.Name] = p.Enum[i-1]
+			}
+			a.renderBuilder()
+			return nil
+		},
+	})
+}
+
+// openEditModal shows the single-line edit dialog. target ("pane:key") tells
+// confirmEdit where the value goes.
+func (a *App) openEditModal(g *gocui.Gui, title, currentVal, target string) error {
+	a.editing = true
+	a.editTarget = target
+
+	if err := a.layoutEdit(); err != nil {
+		return err
+	}
+	// always update content and focus (in case view already existed)
+	if ev, err := g.View("edit"); err == nil {
+		ev.Title = fmt.Sprintf(" %s (enter=ok, esc=cancel) ", title)
+		ev.Clear()
+		fmt.Fprint(ev, currentVal)
+		ev.SetCursor(len(currentVal), 0)
+	}
+	g.SetCurrentView("edit")
+	return nil
+}
+
+// layoutEdit centers the edit modal on the last laid-out screen, creating it
+// if needed, so it follows the terminal when it's resized.
+func (a *App) layoutEdit() error {
+	width := 60
+	if width > a.width-4 {
+		width = a.width - 4
+	}
+	height := 3
+	x0 := (a.width - width) / 2
+	y0 := (a.height - height) / 2
+	ev, err := a.g.SetView("edit", x0, y0, x0+width, y0+height)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ev.Editable = true
+		ev.Editor = singleLineEditor{}
+		ev.BgColor = gocui.ColorBlack
+		ev.FgColor = gocui.ColorWhite
+	}
+	return nil
 }
 
 func (a *App) closeEdit() error {
@@ -1612,6 +3180,7 @@ func (a *App) closeEdit() error {
 	}
 	a.editing = false
 	a.editTarget = ""
+	a.curlLines = ""
 	a.setBuilderFocus()
 	return nil
 }
@@ -1633,76 +3202,696 @@ func (a *App) confirmEdit(g *gocui.Gui, v *gocui.View) error {
 		a.pathVals[key] = val
 	case "query":
 		a.queryVals[key] = val
+	case "header":
+		a.headerVals[key] = val
 	case "body":
-		a.bodyVals[key] = val
+		// an empty value omits the field; space includes it empty
+		if val == "" {
+			delete(a.bodyVals, key)
+		} else {
+			a.bodyVals[key] = val
+		}
+	case "bodytext":
+		a.bodyRaw = val
+	case "notes":
+		a.saveNote(key, val)
+	case "preset":
+		a.closeEdit()
+		a.savePreset(val)
+		a.renderBuilder()
+		return nil
+	case "curl":
+		cmdline := a.curlLines + viewText(v)
+		if curl.Incomplete(cmdline) {
+			// more lines of a pasted command are on their way
+			a.curlLines = cmdline + "\n"
+			v.Clear()
+			v.SetCursor(0, 0)
+			v.SetOrigin(0, 0)
+			return nil
+		}
+		a.closeEdit()
+		a.importCurl(cmdline)
+		return nil
+	case "server":
+		a.closeEdit()
+		return a.setServerVar(key, val)
+	case "profile":
+		a.closeEdit()
+		a.addAuthProfile(val)
+		return nil
+	case "search":
+		a.closeEdit()
+		a.setResponseSearch(val)
+		return nil
+	case "sendheader":
+		a.closeEdit()
+		a.setPreviewHeader(key, val)
+		a.showHeaderPreview()
+		return nil
+	}
+
+	a.closeEdit()
+	a.renderBuilder()
+	return nil
+}
+
+// operationKey identifies an operation for saved presets: its operationId,
+// falling back to "METHOD /path".
+func operationKey(ep model.Endpoint) string {
+	if ep.OperationID != "" {
+		return ep.OperationID
+	}
+	return endpointKey(ep)
+}
+
+func copyVals(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// authSchemesInUse names the scheme(s) of the first security requirement we
+// hold credentials for, joined with "+".
+func (a *App) authSchemesInUse(ep model.Endpoint) string {
+	for _, req := range ep.Security {
+		var names []string
+		for name := range req {
+			if st, ok := a.authStore[name]; !ok || strings.TrimSpace(st.token) == "" {
+				names = nil
+				break
+			}
+			names = append(names, name)
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			return strings.Join(names, "+")
+		}
+	}
+	return ""
+}
+
+func (a *App) promptSavePreset(g *gocui.Gui, v *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	return a.openEditModal(g, "save preset as", "", "preset:save")
+}
+
+// savePreset stores the builder state under name for the active operation.
+func (a *App) savePreset(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	ep := a.activeEndpoint
+	a.specConfig().SavePreset(operationKey(ep), config.Preset{
+		Name:       name,
+		PathVals:   copyVals(a.pathVals),
+		QueryVals:  copyVals(a.queryVals),
+		HeaderVals: copyVals(a.headerVals),
+		BodyVals:   copyVals(a.bodyVals),
+		BodyRaw:    a.bodyRaw,
+		Auth:       a.authSchemesInUse(ep),
+	})
+	if err := a.cfg.Save(); err != nil {
+		a.errorMsg = "save config: " + err.Error()
+		return
+	}
+	a.errorMsg = ""
+	a.notify("saved preset " + name)
+}
+
+// openPresets lists the saved presets for the active operation.
+func (a *App) openPresets(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	op := operationKey(a.activeEndpoint)
+	presets := a.specConfig().Presets[op]
+	if len(presets) == 0 {
+		a.errorMsg = "no presets saved for this endpoint (press s to save one)"
+		return nil
+	}
+	items := make([]string, len(presets))
+	for i, p := range presets {
+		items[i] = p.Name
+	}
+	a.openPicker(&picker{
+		title: "Presets (enter=load, ctrl+d=delete)",
+		items: items,
+		onPick: func(i int) error {
+			a.loadPreset(a.specConfig().Presets[op][i])
+			return nil
+		},
+		onDelete: func(i int) error {
+			sc := a.specConfig()
+			sc.DeletePreset(op, sc.Presets[op][i].Name)
+			if err := a.cfg.Save(); err != nil {
+				a.errorMsg = "save config: " + err.Error()
+			}
+			return nil
+		},
+	})
+	return nil
+}
+
+func (a *App) loadPreset(p config.Preset) {
+	a.pathVals = copyVals(p.PathVals)
+	a.queryVals = copyVals(p.QueryVals)
+	a.headerVals = copyVals(p.HeaderVals)
+	a.bodyVals = copyVals(p.BodyVals)
+	a.bodyRaw = p.BodyRaw
+	a.clearBodyDraft()
+	a.errorMsg = ""
+	if p.Auth != "" && a.authHeadersForEndpoint(a.activeEndpoint) == nil {
+		a.errorMsg = "loaded preset " + p.Name + " (it used auth " + p.Auth + ", which isn't set; press A)"
+		return
+	}
+	a.notify("loaded preset " + p.Name)
+}
+
+func (a *App) executeRequest(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	if msg := a.requireBaseURL(); msg != "" {
+		a.errorMsg = msg
+		return nil
+	}
+	req, err := a.buildActiveRequest()
+	if err != nil {
+		a.errorMsg = err.Error()
+		var ve *httpclient.ValidationError
+		if errors.As(err, &ve) {
+			a.focusField(ve.Pane, ve.Field)
+		}
+		return nil
+	}
+	if a.cacheResponses {
+		if res, ok := a.cachedResponse(req); ok {
+			a.showCached(req, res)
+			return nil
+		}
+	}
+	a.send(req, false)
+	return nil
+}
+
+// focusField moves the builder focus to the row of field in the named pane,
+// e.g. to the value a request was rejected for.
+func (a *App) focusField(pane, field string) {
+	for p, name := range paneNames {
+		if name == pane {
+			a.pane = p
+		}
+	}
+	a.ensureValidPane()
+	a.updatePanelColors()
+	a.setBuilderFocus()
+	v, err := a.g.View(paneNames[a.pane])
+	if err != nil || field == "" {
+		return
+	}
+	_, height := v.Size()
+	for i, line := range viewLines(v) {
+		if lineKey(line) != field {
+			continue
+		}
+		oy := 0
+		if height > 0 && i >= height {
+			oy = i - height + 1
+		}
+		v.SetOrigin(0, oy)
+		v.SetCursor(0, i-oy)
+		return
+	}
+}
+
+// defaultRequestTimeout bounds a request when no --timeout is configured.
+const defaultRequestTimeout = 20 * time.Second
+
+// requestTimeout is the per-method timeout if one is set, else the global one.
+func (a *App) requestTimeout(method string) time.Duration {
+	if d := a.methodTimeouts[strings.ToUpper(method)]; d > 0 {
+		return d
+	}
+	if a.timeout > 0 {
+		return a.timeout
+	}
+	return defaultRequestTimeout
+}
+
+// activeBaseURL is where requests for the active endpoint go: its own base
+// URL when merged from another spec, else the app's.
+func (a *App) activeBaseURL() string {
+	return strings.TrimSpace(firstNonEmpty(a.activeEndpoint.BaseURL, a.baseURL))
+}
+
+// requireBaseURL returns the error to show when there's no base URL to send
+// the active endpoint's request to, and "" when there is one.
+func (a *App) requireBaseURL() string {
+	if a.activeBaseURL() != "" {
+		return ""
+	}
+	return "base URL unknown; pass --base-url or set XHARK_BASE_URL, pick one of the spec's servers with Ctrl+S in the endpoint list, or load the spec from an http(s) URL"
+}
+
+// buildActiveRequest resolves the builder state for the active endpoint into a
+// request, including any auth headers we hold for it.
+func (a *App) buildActiveRequest() (httpclient.RequestSpec, error) {
+	var headers, query map[string]string
+	if !a.skipAuth {
+		headers, query, _ = a.authForEndpoint(a.activeEndpoint)
+	}
+	req, err := httpclient.BuildRequest(&a.client, a.baseURL, a.activeEndpoint, a.pathVals, a.queryVals, a.headerVals, a.bodyVals, a.bodyRaw)
+	if err != nil {
+		return httpclient.RequestSpec{}, err
+	}
+	if len(query) > 0 {
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return httpclient.RequestSpec{}, err
+		}
+		// append rather than re-encode, which would turn bare ?flag params
+		// into ?flag=
+		extra := url.Values{}
+		for k, v := range query {
+			extra.Set(k, v)
+			u.RawQuery = dropQueryParam(u.RawQuery, k)
+		}
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += extra.Encode()
+		req.URL = u.String()
+	}
+	if len(headers) > 0 {
+		if req.Headers == nil {
+			req.Headers = map[string]string{}
+		}
+		for k, v := range headers {
+			if strings.EqualFold(k, "Cookie") {
+				// keep cookies sent as header params alongside the auth ones
+				for old, cur := range req.Headers {
+					if strings.EqualFold(old, k) && strings.TrimSpace(cur) != "" {
+						v = cur + "; " + v
+					}
+				}
+			}
+			httpclient.SetHeader(req.Headers, k, v)
+		}
+	}
+	return req, nil
+}
+
+// dropQueryParam removes name from a raw query string, so an auth key
+// replaces a value typed into the query pane instead of being sent twice.
+func dropQueryParam(raw, name string) string {
+	var keep []string
+	for _, part := range strings.Split(raw, "&") {
+		k, _, _ := strings.Cut(part, "=")
+		if uk, err := url.QueryUnescape(k); err == nil && uk == name {
+			continue
+		}
+		if part != "" {
+			keep = append(keep, part)
+		}
+	}
+	return strings.Join(keep, "&")
+}
+
+// yankURL copies the fully-resolved request URL to the clipboard. On the
+// response screen it uses the request that was sent; in the builder it
+// resolves the current params.
+func (a *App) yankURL(*gocui.Gui, *gocui.View) error {
+	if a.editing {
+		return nil
+	}
+	var u string
+	switch a.scr {
+	case screenResponse:
+		u = a.lastReq.URL
+	case screenBuilder:
+		if msg := a.requireBaseURL(); msg != "" {
+			a.errorMsg = msg
+			return nil
+		}
+		req, err := httpclient.BuildRequest(&a.client, a.baseURL, a.activeEndpoint, a.pathVals, a.queryVals, a.headerVals, a.bodyVals, a.bodyRaw)
+		if err != nil {
+			a.errorMsg = err.Error()
+			return nil
+		}
+		u = req.URL
+	}
+	if u == "" {
+		return nil
+	}
+	if err := copyToClipboard(u); err != nil {
+		a.errorMsg = err.Error()
+		return nil
+	}
+	a.errorMsg = ""
+	a.notify("copied to clipboard: " + u)
+	return nil
+}
+
+// openEndpointInBrowser opens the resolved URL of a GET endpoint in the default
+// browser. Requests that depend on headers (e.g. Authorization) can't be
+// reproduced by a browser, so those are refused with a footer note.
+func (a *App) openEndpointInBrowser(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	if a.activeEndpoint.Method != "GET" {
+		a.errorMsg = "open in browser: only GET endpoints are supported"
+		return nil
+	}
+	if msg := a.requireBaseURL(); msg != "" {
+		a.errorMsg = msg
+		return nil
+	}
+	req, err := a.buildActiveRequest()
+	if err != nil {
+		a.errorMsg = err.Error()
+		return nil
+	}
+	for k, v := range req.Headers {
+		if strings.TrimSpace(v) != "" {
+			a.errorMsg = fmt.Sprintf("open in browser: request needs the %s header", k)
+			return nil
+		}
+	}
+	if err := openInBrowser(req.URL); err != nil {
+		a.errorMsg = err.Error()
+		return nil
+	}
+	a.errorMsg = ""
+	a.notify("opened: " + req.URL)
+	return nil
+}
+
+func (a *App) rerun(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenResponse {
+		return nil
+	}
+	if a.lastReq.URL == "" {
+		return nil
+	}
+	a.send(a.lastReq, true)
+	return nil
+}
+
+// setResponse replaces the current response and drops its cached rendering.
+func (a *App) setResponse(res httpclient.Result) {
+	a.lastRes = res
+	a.resErr = nil
+	a.resBody = ""
+	a.resExpanded = false
+	a.resMatch = 0
+}
+
+// yankResponseBody copies the response body to the clipboard, indented like
+// the response view but without colors or depth/item limits, so it pastes as
+// valid JSON.
+func (a *App) yankResponseBody(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenResponse || a.resErr != nil {
+		return nil
+	}
+	r := a.lastRes
+	if len(r.Raw) == 0 {
+		a.errorMsg = "response body is empty"
+		return nil
+	}
+	body := stripANSI(httpclient.FormatBody(r.Headers["content-type"], r.Raw, httpclient.FormatOptions{}))
+	if err := copyToClipboard(body); err != nil {
+		a.errorMsg = err.Error()
+		return nil
+	}
+	a.errorMsg = ""
+	a.notify(fmt.Sprintf("copied response body (%d bytes)", len(body)))
+	return nil
+}
+
+// exportHAR writes every request sent this session, with its response, to a
+// HAR 1.2 file in the working directory.
+func (a *App) exportHAR(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenHistory {
+		return nil
+	}
+	a.writeHAR(a.history)
+	return nil
+}
+
+// exportSelectedHAR writes the request selected on the history screen.
+func (a *App) exportSelectedHAR(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenHistory {
+		return nil
+	}
+	if h, ok := a.selectedHistory(); ok {
+		a.writeHAR([]historyEntry{h})
+	}
+	return nil
+}
+
+// writeHAR saves entries as a HAR file, readable by the user only and with
+// credentials redacted, since HAR files tend to get attached to tickets.
+func (a *App) writeHAR(history []historyEntry) {
+	var entries []har.Entry
+	for _, h := range history {
+		if h.err != nil {
+			// no response to export
+			continue
+		}
+		e := h.Entry
+		e.Request.URL = a.redactURL(e.Request.URL)
+		e.Request.Headers = a.redactHeaders(e.Request.Headers)
+		e.Response.Headers = a.redactHeaders(e.Response.Headers)
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 {
+		a.errorMsg = "nothing to export"
+		return
+	}
+	b, err := har.Build(entries)
+	if err != nil {
+		a.errorMsg = "har: " + err.Error()
+		return
+	}
+	file := fmt.Sprintf("%s-%s.har", fileSlug(firstNonEmpty(a.specTitle, "xhark")), time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(file, b, 0o600); err != nil {
+		a.errorMsg = "har: " + err.Error()
+		return
+	}
+	a.errorMsg = ""
+	a.notify(fmt.Sprintf("exported %d requests to %s", len(entries), file))
+}
+
+// saveResponseBody writes the response body, as received, to a file in the
+// working directory named after the operation, with an extension matching
+// its content type.
+func (a *App) saveResponseBody(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenResponse || a.resErr != nil {
+		return nil
+	}
+	r := a.lastRes
+	if len(r.Raw) == 0 {
+		a.errorMsg = "response body is empty"
+		return nil
+	}
+	name := firstNonEmpty(a.activeEndpoint.OperationID, firstNonEmpty(a.specTitle, "response"))
+	file := fmt.Sprintf("%s-%s%s", fileSlug(name), time.Now().Format("20060102-150405"), extForContentType(r.Headers["content-type"]))
+	if err := os.WriteFile(file, r.Raw, 0o644); err != nil {
+		a.errorMsg = "save: " + err.Error()
+		return nil
 	}
+	a.errorMsg = ""
+	a.notify(fmt.Sprintf("saved response body (%d bytes) to %s", len(r.Raw), file))
+	return nil
+}
 
-	a.closeEdit()
-	a.renderBuilder()
+// extForContentType picks a file extension for a response content type,
+// falling back to .txt.
+func extForContentType(ct string) string {
+	mt, _, _ := strings.Cut(strings.ToLower(ct), ";")
+	mt = strings.TrimSpace(mt)
+	switch {
+	case httpclient.IsJSONMediaType(mt):
+		return ".json"
+	case mt == "image/svg+xml":
+		return ".svg"
+	case httpclient.IsXMLMediaType(mt):
+		return ".xml"
+	case mt == "text/html":
+		return ".html"
+	case mt == "text/csv":
+		return ".csv"
+	case mt == "application/yaml", mt == "application/x-yaml", mt == "text/yaml":
+		return ".yaml"
+	case mt == "application/pdf":
+		return ".pdf"
+	case mt == "application/zip":
+		return ".zip"
+	case mt == "image/png":
+		return ".png"
+	case mt == "image/jpeg":
+		return ".jpg"
+	case mt == "image/gif":
+		return ".gif"
+	case mt == "image/webp":
+		return ".webp"
+	}
+	return ".txt"
+}
+
+// expandResponse renders the full body of a truncated response.
+func (a *App) expandResponse(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenResponse || a.resExpanded {
+		return nil
+	}
+	a.resExpanded = true
+	a.resBody = ""
+	a.renderResponse()
 	return nil
 }
 
-func (a *App) executeRequest(*gocui.Gui, *gocui.View) error {
-	if a.scr != screenBuilder || a.editing {
+// adjustJSONDepth steps the JSON depth limit. 0 means unlimited; stepping
+// down from unlimited starts at a shallow default.
+func (a *App) adjustJSONDepth(delta int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if a.scr != screenResponse {
+			return nil
+		}
+		d := a.jsonFormat.MaxDepth
+		switch {
+		case d == 0 && delta < 0:
+			d = 4
+		case d == 0:
+			return nil
+		default:
+			d += delta
+			if d < 1 {
+				d = 1
+			}
+		}
+		a.jsonFormat.MaxDepth = d
+		a.resBody = ""
+		a.renderResponse()
 		return nil
 	}
-	if strings.TrimSpace(a.baseURL) == "" {
-		a.errorMsg = "base URL unknown (spec missing servers); set XHARK_BASE_URL, or load spec from an http(s) URL"
+}
+
+// toggleSortKeys switches JSON object keys between the order the server sent
+// them and sorted.
+func (a *App) toggleSortKeys(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenResponse {
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
+	a.jsonFormat.SortKeys = !a.jsonFormat.SortKeys
+	a.resBody = ""
+	a.renderResponse()
+	return nil
+}
 
-	headers := a.authHeadersForEndpoint(a.activeEndpoint)
-	req, err := httpclient.BuildRequest(a.baseURL, a.activeEndpoint, a.pathVals, a.queryVals, a.bodyVals, a.bodyRaw)
-	if err != nil {
-		a.errorMsg = err.Error()
+// toggleCompactJSON puts small JSON arrays and objects on one line.
+func (a *App) toggleCompactJSON(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenResponse {
 		return nil
 	}
-	if len(headers) > 0 {
-		if req.Headers == nil {
-			req.Headers = map[string]string{}
+	a.jsonFormat.Compact = !a.jsonFormat.Compact
+	a.resBody = ""
+	a.renderResponse()
+	return nil
+}
+
+// adjustJSONMaxItems halves or doubles the array length limit. 0 means
+// unlimited; doubling past 1000 goes back to unlimited.
+func (a *App) adjustJSONMaxItems(dir int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if a.scr != screenResponse {
+			return nil
 		}
-		for k, v := range headers {
-			req.Headers[k] = v
+		n := a.jsonFormat.MaxItems
+		switch {
+		case n == 0 && dir < 0:
+			n = 50
+		case n == 0:
+			return nil
+		case dir < 0:
+			n /= 2
+			if n < 1 {
+				n = 1
+			}
+		default:
+			n *= 2
+			if n > 1000 {
+				n = 0
+			}
 		}
-	}
-	res, err := httpclient.Execute(ctx, req)
-	if err != nil {
-		a.errorMsg = err.Error()
+		a.jsonFormat.MaxItems = n
+		a.resBody = ""
+		a.renderResponse()
 		return nil
 	}
-	_ = req
+}
 
-	a.lastReq = req
-	a.lastRes = res
-	a.scr = screenResponse
-	a.errorMsg = ""
-	return nil
+// responseBody returns the rendered body of the current response, truncating
+// it to maxBodyBytes unless expanded.
+func (a *App) responseBody() string {
+	if a.resBody != "" {
+		return a.resBody
+	}
+	r := a.lastRes
+	ct := r.Headers["content-type"]
+	if a.resExpanded || a.maxBodyBytes <= 0 || len(r.Raw) <= a.maxBodyBytes {
+		a.resBody = httpclient.FormatBody(ct, r.Raw, a.jsonFormat)
+		return a.resBody
+	}
+	// cut on a rune boundary so a multi-byte character isn't split into
+	// replacement characters; binary bodies move back at most a few bytes
+	n := a.maxBodyBytes
+	for i := 0; i < utf8.UTFMax-1 && n > 0 && !utf8.RuneStart(r.Raw[n]); i++ {
+		n--
+	}
+	a.resBody = string(r.Raw[:n]) + fmt.Sprintf("\n%s... truncated: showing %d of %d KB (press x to expand)%s",
+		colorYellow, a.maxBodyBytes/1024, (len(r.Raw)+1023)/1024, colorReset)
+	return a.resBody
 }
 
-func (a *App) rerun(*gocui.Gui, *gocui.View) error {
+// toggleDiff switches the response view between the body and a diff against
+// the response that the last rerun replaced.
+func (a *App) toggleDiff(*gocui.Gui, *gocui.View) error {
 	if a.scr != screenResponse {
 		return nil
 	}
-	if a.lastReq.URL == "" {
+	if a.prevRes == nil {
+		a.errorMsg = "nothing to diff yet (press r to rerun)"
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
-	res, err := httpclient.Execute(ctx, a.lastReq)
-	if err != nil {
-		a.errorMsg = err.Error()
-		return nil
+	a.showDiff = !a.showDiff
+	if v, err := a.g.View("response"); err == nil {
+		v.SetOrigin(0, 0)
 	}
-	a.lastRes = res
 	a.renderResponse()
 	return nil
 }
 
 func (a *App) scrollResponse(delta int) func(*gocui.Gui, *gocui.View) error {
+	scroll := a.scrollView(delta)
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if a.scr != screenResponse {
+			return nil
+		}
+		return scroll(g, v)
+	}
+}
+
+// scrollView moves a read-only view's origin by one line.
+func (a *App) scrollView(delta int) func(*gocui.Gui, *gocui.View) error {
 	return func(g *gocui.Gui, v *gocui.View) error {
-		if a.scr != screenResponse || v == nil {
+		if v == nil {
 			return nil
 		}
 		ox, oy := v.Origin()
@@ -1719,20 +3908,41 @@ func (a *App) renderFooter() {
 	if v, err := a.g.View("footer"); err == nil {
 		v.Clear()
 		msg := a.errorMsg
+		if a.toast != "" {
+			msg = a.toast
+		}
+		if a.inFlight != nil {
+			msg = a.inFlightStatus()
+		}
+		if a.reloading {
+			msg = colorYellow + "reloading spec..." + colorReset
+		}
 		if msg == "" {
 			if a.authOpen {
-				msg = "auth: enter=edit/save   tab=next field   ctrl+d=clear   esc=close"
+				msg = "auth: enter=edit/save   tab=next field   ctrl+d=clear   ctrl+p=profiles   esc=close"
 			} else {
 				switch a.scr {
 				case screenEndpoints:
-					msg = "type: filter   1-5: quick select   enter: select   esc: back   A: auth   q: quit"
+					msg = "type: filter   1-5: quick select   enter: select   left/right+ctrl+t: facets   ctrl+p: pin   ctrl+g: group by tag   ctrl+d: deprecated   ctrl+b: badges   ctrl+x: export   ctrl+u: curl   ctrl+o: specs   ctrl+s: servers   f5: reload   esc: back   A: auth   q: quit"
 				case screenBuilder:
-					msg = "tab: switch pane   enter: edit   d: reset param   ctrl+r: run   A: auth   esc: back"
-					if a.pane == paneBody && a.activeEndpoint.Body != nil {
-						msg = "tab: switch pane   enter: edit json ($EDITOR)   d: reset param   ctrl+r: run   A: auth   esc: back"
+					msg = "tab: switch pane   enter: edit   d: reset param   e: examples   y: copy url   u: copy curl   o: open   t: raw path   z: maximize   i: inspect   s/p: save/load preset   n: skip auth   h: review headers   m: notes   ctrl+r: run   A: auth   esc: back"
+					if a.pane == paneQuery {
+						msg = "tab: switch pane   enter: edit   d: reset param   f: bool flag   e: examples   y: copy url   u: copy curl   o: open   t: raw path   z: maximize   i: inspect   s/p: save/load preset   n: skip auth   h: review headers   m: notes   ctrl+r: run   A: auth   esc: back"
+					}
+					if a.pane == paneBody && a.activeEndpoint.Body != nil && a.noEditor {
+						msg = "tab: switch pane   enter: edit   d: reset param   space: include if empty   v: schema   e: examples   y: copy url   u: copy curl   o: open   t: raw path   z: maximize   i: inspect   s/p: save/load preset   n: skip auth   h: review headers   m: notes   ctrl+r: run   A: auth   esc: back"
 					}
+					if a.pane == paneBody && a.activeEndpoint.Body != nil && !a.noEditor {
+						msg = "tab: switch pane   enter: edit json ($EDITOR)   d: reset param   space: include if empty   v: schema   e: examples   y: copy url   u: copy curl   o: open   t: raw path   z: maximize   i: inspect   s/p: save/load preset   n: skip auth   h: review headers   m: notes   ctrl+r: run   A: auth   esc: back"
+					}
+				case screenInspect:
+					msg = "up/down: scroll   esc: back to builder"
+				case screenHistory:
+					msg = "up/down: select   enter: open in builder   r: rerun   c: compare   h/H: har (selected/all)   esc: back"
+				case screenCompare:
+					msg = "up/down: scroll   r: send both again   esc: back to history"
 				case screenResponse:
-					msg = "up/down: scroll   r: rerun   enter: back to endpoints   A: auth   esc: back"
+					msg = "up/down: scroll   r: rerun   d: diff   x: expand   </>: depth   [/]: items   k: key order   z: compact   /: search   n/N: next/prev match   y: copy url   u: copy curl   c: copy body   s: save body   enter: back to endpoints   A: auth   esc: back"
 				}
 			}
 		}
@@ -1750,18 +3960,53 @@ func (a *App) renderFilter() {
 }
 
 func (a *App) recomputeFilter() {
+	a.filterEndpoints()
+	a.pinToTop()
+	a.groupRows()
+}
+
+// pinToTop moves pinned endpoints to the head of the list, adding them even
+// when the filter doesn't match.
+func (a *App) pinToTop() {
+	sc := a.specConfig()
+	if len(sc.Pinned) == 0 {
+		return
+	}
+	var pinned, rest []int
+	for i, ep := range a.endpoints {
+		if sc.IsPinned(endpointKey(ep)) {
+			pinned = append(pinned, i)
+		}
+	}
+	if len(pinned) == 0 {
+		return
+	}
+	for _, idx := range a.filtered {
+		if !sc.IsPinned(endpointKey(a.endpoints[idx])) {
+			rest = append(rest, idx)
+		}
+	}
+	a.filtered = append(pinned, rest...)
+}
+
+func (a *App) filterEndpoints() {
 	needle := strings.TrimSpace(a.filter)
 	if needle == "" {
 		a.filtered = a.filtered[:0]
-		for i := range a.endpoints {
-			a.filtered = append(a.filtered, i)
+		for i, ep := range a.endpoints {
+			if a.listable(ep) {
+				a.filtered = append(a.filtered, i)
+			}
 		}
 		return
 	}
 
 	var scored []scoredIdx
 	for i, ep := range a.endpoints {
-		cand := strings.ToLower(ep.Method + " " + ep.Path + " " + firstNonEmpty(ep.Summary, ep.OperationID))
+		if !a.listable(ep) {
+			continue
+		}
+		cand := strings.ToLower(ep.Service + " " + ep.Method + " " + ep.Path + " " + firstNonEmpty(ep.Summary, ep.OperationID))
 		if s, ok := fuzzyMatchScore(needle, cand); ok {
 			scored = append(scored, scoredIdx{idx: i, score: s})
 		}
@@ -1776,9 +4021,6 @@ func (a *App) recomputeFilter() {
 	for _, s := range scored {
 		a.filtered = append(a.filtered, s.idx)
 	}
-	if a.selected >= len(a.filtered) {
-		a.selected = 0
-	}
 }
 
 func (a *App) renderEndpoints() {
@@ -1787,21 +4029,112 @@ func (a *App) renderEndpoints() {
 		return
 	}
 	v.Clear()
-
+	width, _ := v.Size()
+
+	if a.grouped {
+		n := 0
+		for _, r := range a.rows {
+			if r.idx < 0 {
+				fmt.Fprintln(v, truncateVisible(a.groupHeader(r), width))
+				continue
+			}
+			fmt.Fprintln(v, truncateVisible(a.endpointLine(a.endpoints[r.idx], n), width))
+			n++
+		}
+		v.SetCursor(0, a.selected)
+		return
+	}
 	for i, idx := range a.filtered {
-		ep := a.endpoints[idx]
-		label := firstNonEmpty(ep.Summary, ep.OperationID)
-		if label != "" {
-			label = " - " + label
+		fmt.Fprintln(v, truncateVisible(a.endpointLine(a.endpoints[idx], i), width))
+	}
+	v.SetCursor(0, a.selected)
+}
+
+// endpointLine renders the i-th endpoint of the list (the first five get a
+// quick-select number).
+func (a *App) endpointLine(ep model.Endpoint, i int) string {
+	label := firstNonEmpty(ep.Summary, ep.OperationID)
+	if label != "" {
+		label = " - " + label
+	}
+	// show number prefix for top 5 results
+	prefix := "  "
+	if i < 5 {
+		prefix = fmt.Sprintf("%d ", i+1)
+	}
+	pin := "  "
+	if a.specConfig().IsPinned(endpointKey(ep)) {
+		pin = colorYellow + "* " + colorReset
+	}
+	badges := ""
+	if !a.hideBadges {
+		badges = a.endpointBadges(ep)
+	}
+	service := ""
+	if ep.Service != "" {
+		service = colorDim + ep.Service + colorReset + " "
+	}
+	method, epPath := colorizeMethod(ep.Method), highlightPathParams(ep.Path)
+	if ep.Deprecated {
+		// gocui has no strikethrough; dim the whole operation instead
+		method = colorDim + padRight(ep.Method, 6) + colorReset
+		epPath = colorDim + ep.Path + colorReset
+	}
+	return fmt.Sprintf("%s%s%s%s  %s%s%s", prefix, pin, service, method, epPath, badges, label)
+}
+
+// endpointBadges renders compact markers for deprecated endpoints, auth
+// (yellow when missing, green when we hold credentials) and required bodies.
+func (a *App) endpointBadges(ep model.Endpoint) string {
+	var b strings.Builder
+	if ep.Deprecated {
+		b.WriteString(" " + colorRed + "[deprecated]" + colorReset)
+	}
+	if len(ep.Security) > 0 {
+		color := colorYellow
+		if a.authHeadersForEndpoint(ep) != nil {
+			color = colorGreen
 		}
-		// show number prefix for top 5 results
-		prefix := "  "
-		if i < 5 {
-			prefix = fmt.Sprintf("%d ", i+1)
+		b.WriteString(" " + color + "[auth]" + colorReset)
+	}
+	if ep.Body != nil && ep.Body.Required {
+		b.WriteString(" " + colorMagenta + "[body]" + colorReset)
+	}
+	return b.String()
+}
+
+func (a *App) toggleDeprecated(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints {
+		return nil
+	}
+	a.hideDeprecated = !a.hideDeprecated
+	cur, ok := a.selectedEndpoint()
+	a.recomputeFilter()
+	if ok {
+		a.selectEndpointIdx(cur)
+	}
+	a.renderEndpoints()
+	n := 0
+	for _, ep := range a.endpoints {
+		if ep.Deprecated {
+			n++
 		}
-		fmt.Fprintf(v, "%s%s  %s%s\n", prefix, colorizeMethod(ep.Method), highlightPathParams(ep.Path), label)
 	}
-	v.SetCursor(0, a.selected)
+	if a.hideDeprecated {
+		a.notify(fmt.Sprintf("deprecated endpoints hidden (%d)", n))
+	} else {
+		a.notify(fmt.Sprintf("deprecated endpoints shown (%d)", n))
+	}
+	return nil
+}
+
+func (a *App) toggleBadges(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints {
+		return nil
+	}
+	a.hideBadges = !a.hideBadges
+	a.renderEndpoints()
+	return nil
 }
 
 // ansi colors
@@ -1826,13 +4159,25 @@ func (a *App) renderBuilder() {
 		if label != "" {
 			label = " - " + label
 		}
-		fmt.Fprintf(v, "%s  %s%s\n", colorizeMethod(a.activeEndpoint.Method), highlightPathParams(a.activeEndpoint.Path), label)
-		if strings.TrimSpace(a.bodyRaw) != "" {
+		p := highlightPathParams(a.activeEndpoint.Path)
+		if !a.showRawPath {
+			p = resolvedPathPreview(a.activeEndpoint, a.pathVals)
+		}
+		fmt.Fprintf(v, "%s  %s%s\n", colorizeMethod(a.activeEndpoint.Method), p, label)
+		if a.bodyDraft != "" {
+			fmt.Fprintf(v, "%sbody: invalid json draft (enter on body to fix)%s\n", colorRed, colorReset)
+		} else if strings.TrimSpace(a.bodyRaw) != "" && !isTextBody(a.activeEndpoint) {
 			fmt.Fprintf(v, "%sbody: raw json set%s\n", colorCyan, colorReset)
 		}
 		if len(a.activeEndpoint.Security) > 0 {
-			if a.authHeadersForEndpoint(a.activeEndpoint) != nil {
-				fmt.Fprintf(v, "%sauth: set%s\n", colorCyan, colorReset)
+			if a.skipAuth {
+				fmt.Fprintf(v, "%sauth: skipped (n to send it)%s\n", colorYellow, colorReset)
+			} else if a.authHeadersForEndpoint(a.activeEndpoint) != nil {
+				profile := ""
+				if a.authProfile != defaultAuthProfile {
+					profile = " (profile " + a.authProfile + ")"
+				}
+				fmt.Fprintf(v, "%sauth: set%s%s\n", colorCyan, profile, colorReset)
 			} else {
 				fmt.Fprintf(v, "%sauth: required (press A)%s\n", colorYellow, colorReset)
 			}
@@ -1871,12 +4216,26 @@ func (a *App) renderBuilder() {
 			}
 			var display string
 			var color string
+			_, set := a.queryVals[p.Name]
 			if val != "" {
 				display = val
 				color = colorGreen
+			} else if set && p.AllowEmptyValue {
+				display = "(empty)"
+				color = colorGreen
 			} else {
 				var hint string
 				var parts []string
+				switch p.Type {
+				case model.TypeArray:
+					if p.ItemType != "" && p.ItemType != model.TypeUnknown {
+						parts = append(parts, "list of "+string(p.ItemType)+": a,b,c")
+					} else {
+						parts = append(parts, "list: a,b,c")
+					}
+				case model.TypeObject:
+					parts = append(parts, "object: k=v,k2=v2")
+				}
 				if len(p.Enum) > 0 {
 					parts = append(parts, strings.Join(p.Enum, "|"))
 				}
@@ -1894,10 +4253,14 @@ func (a *App) renderBuilder() {
 				}
 				color = colorCyan
 			}
+			flag := ""
+			if p.Type == model.TypeBoolean && p.AllowEmptyValue {
+				flag = " " + colorDim + "[flag]" + colorReset
+			}
 			if display != "" {
-				fmt.Fprintf(v, "%s%s = %s%s%s\n", req, p.Name, color, display, colorReset)
+				fmt.Fprintf(v, "%s%s = %s%s%s%s\n", req, p.Name, color, display, colorReset, flag)
 			} else {
-				fmt.Fprintf(v, "%s%s = \n", req, p.Name)
+				fmt.Fprintf(v, "%s%s = %s\n", req, p.Name, flag)
 			}
 		}
 		if len(a.activeEndpoint.QueryParams) == 0 {
@@ -1905,15 +4268,56 @@ func (a *App) renderBuilder() {
 		}
 	}
 
+	if v, err := a.g.View("header"); err == nil {
+		v.Title = "Header Params"
+		v.Clear()
+		for _, p := range a.activeEndpoint.HeaderParams {
+			val := a.headerVals[p.Name]
+			req := ""
+			if p.Required {
+				req = "*"
+			}
+			if val == "" && p.Example != "" {
+				fmt.Fprintf(v, "%s%s = %s%s%s\n", req, p.Name, colorDim, p.Example, colorReset)
+			} else {
+				fmt.Fprintf(v, "%s%s = %s\n", req, p.Name, val)
+			}
+		}
+	}
+
 	if v, err := a.g.View("body"); err == nil {
 		v.Title = "Body"
+		if isFormBody(a.activeEndpoint) {
+			v.Title = "Body (form-urlencoded)"
+		}
 		v.Clear()
 		if a.activeEndpoint.Body == nil {
 			fmt.Fprintln(v, "(no body)")
 			return
 		}
+		if a.activeEndpoint.Body.Text {
+			v.Title = "Body (text/plain)"
+			switch {
+			case a.bodyRaw != "":
+				fmt.Fprintln(v, a.bodyRaw)
+			case a.activeEndpoint.Body.Example != "":
+				fmt.Fprintf(v, "%s%s%s\n", colorDim, a.activeEndpoint.Body.Example, colorReset)
+			default:
+				fmt.Fprintln(v, "(empty, enter to edit)")
+			}
+			return
+		}
 		if !a.activeEndpoint.Body.Supported {
-			fmt.Fprintln(v, "(body schema unsupported in MVP)")
+			if a.noEditor {
+				fmt.Fprintln(v, "(structured body: edit it in $EDITOR, without --no-editor)")
+			} else {
+				fmt.Fprintln(v, "(structured body: enter to edit the JSON in $EDITOR)")
+			}
+			return
+		}
+		if a.bodySchemaView {
+			v.Title = "Body schema (v: back to values)"
+			renderBodySchema(v, a.activeEndpoint.Body.Fields)
 			return
 		}
 		for _, f := range a.activeEndpoint.Body.Fields {
@@ -1922,11 +4326,37 @@ func (a *App) renderBuilder() {
 			if f.Required {
 				req = "*"
 			}
-			if val == "" && f.Example != "" {
-				fmt.Fprintf(v, "%s%s = %s%s%s\n", req, f.Name, colorDim, f.Example, colorReset)
-			} else {
+			if val != "" {
 				fmt.Fprintf(v, "%s%s = %s\n", req, f.Name, val)
+				continue
+			}
+			if _, set := a.bodyVals[f.Name]; set && !f.Required {
+				sent := "null"
+				if f.Default != "" {
+					sent = f.Default
+				}
+				fmt.Fprintf(v, "%s = %s%s%s %s[included]%s\n", f.Name, colorGreen, sent, colorReset, colorDim, colorReset)
+				continue
+			}
+			// unset: hint with the example, allowed values and what it means
+			var parts []string
+			if f.Example != "" {
+				parts = append(parts, f.Example)
+			}
+			if len(f.Enum) > 0 {
+				parts = append(parts, strings.Join(f.Enum, "|"))
 			}
+			if f.Default != "" {
+				parts = append(parts, "default: "+f.Default)
+			}
+			if f.Description != "" {
+				parts = append(parts, f.Description)
+			}
+			if len(parts) == 0 {
+				fmt.Fprintf(v, "%s%s = \n", req, f.Name)
+				continue
+			}
+			fmt.Fprintf(v, "%s%s = %s%s%s\n", req, f.Name, colorDim, strings.Join(parts, ", "), colorReset)
 		}
 		if len(a.activeEndpoint.Body.Fields) == 0 {
 			fmt.Fprintln(v, "(empty schema)")
@@ -1934,6 +4364,132 @@ func (a *App) renderBuilder() {
 	}
 }
 
+// renderBodySchema lists the body fields as a read-only table of what the
+// spec says about each: type/format, required, enum or constraints, and the
+// description.
+func renderBodySchema(v *gocui.View, fields []model.BodyField) {
+	if len(fields) == 0 {
+		fmt.Fprintln(v, "(empty schema)")
+		return
+	}
+	width, _ := v.Size()
+	nameW, typeW := 0, 0
+	for _, f := range fields {
+		nameW = max(nameW, len(f.Name)+1)
+		typeW = max(typeW, len(fieldTypeLabel(f)))
+	}
+	for _, f := range fields {
+		name := f.Name
+		if f.Required {
+			name = "*" + name
+		}
+		var rules []string
+		if len(f.Enum) > 0 {
+			rules = append(rules, "enum "+strings.Join(f.Enum, "|"))
+		}
+		rules = append(rules, fieldConstraints(f)...)
+		if f.Default != "" {
+			rules = append(rules, "default "+f.Default)
+		}
+		line := fmt.Sprintf("%-*s  %s%-*s%s", nameW, name, colorCyan, typeW, fieldTypeLabel(f), colorReset)
+		if len(rules) > 0 {
+			line += "  " + colorYellow + strings.Join(rules, ", ") + colorReset
+		}
+		if f.Description != "" {
+			line += "  " + colorDim + f.Description + colorReset
+		}
+		fmt.Fprintln(v, truncateVisible(line, width))
+	}
+}
+
+func fieldTypeLabel(f model.BodyField) string {
+	if f.Format != "" {
+		return string(f.Type) + "(" + f.Format + ")"
+	}
+	return string(f.Type)
+}
+
+// fieldConstraints renders a field's numeric bounds, length limits and pattern.
+func fieldConstraints(f model.BodyField) []string {
+	var out []string
+	num := func(x float64) string { return strconv.FormatFloat(x, 'g', -1, 64) }
+	switch {
+	case f.Minimum != nil && f.Maximum != nil:
+		out = append(out, num(*f.Minimum)+".."+num(*f.Maximum))
+	case f.Minimum != nil:
+		out = append(out, ">= "+num(*f.Minimum))
+	case f.Maximum != nil:
+		out = append(out, "<= "+num(*f.Maximum))
+	}
+	switch {
+	case f.MinLength > 0 && f.MaxLength != nil:
+		out = append(out, fmt.Sprintf("len %d..%d", f.MinLength, *f.MaxLength))
+	case f.MinLength > 0:
+		out = append(out, fmt.Sprintf("len >= %d", f.MinLength))
+	case f.MaxLength != nil:
+		out = append(out, fmt.Sprintf("len <= %d", *f.MaxLength))
+	}
+	if f.Pattern != "" {
+		out = append(out, "pattern "+f.Pattern)
+	}
+	return out
+}
+
+// toggleBodySchema switches the body pane between field values and the
+// read-only schema table.
+func (a *App) toggleBodySchema(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing || a.activeEndpoint.Body == nil {
+		return nil
+	}
+	a.bodySchemaView = !a.bodySchemaView
+	a.renderBuilder()
+	return nil
+}
+
+// toggleSkipAuth sends the next requests from the builder without
+// credentials, e.g. to check an endpoint answers 401 when unauthenticated.
+func (a *App) toggleSkipAuth(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	a.skipAuth = !a.skipAuth
+	a.renderBuilder()
+	return nil
+}
+
+// toggleMaximize gives the focused pane the builder's full height, hiding
+// the others; tab still moves between them.
+func (a *App) toggleMaximize(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	a.maximized = !a.maximized
+	return nil
+}
+
+func (a *App) toggleRawPath(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	a.showRawPath = !a.showRawPath
+	a.renderBuilder()
+	return nil
+}
+
+// resolvedPathPreview substitutes the path values entered so far, leaving
+// unfilled params braced (and highlighted) so it's clear what's missing.
+func resolvedPathPreview(ep model.Endpoint, vals map[string]string) string {
+	out := highlightPathParams(ep.Path)
+	for _, p := range ep.PathParams {
+		v := strings.TrimSpace(vals[p.Name])
+		if v == "" {
+			continue
+		}
+		out = strings.ReplaceAll(out, colorCyan+"{"+p.Name+"}"+colorReset, colorGreen+url.PathEscape(v)+colorReset)
+	}
+	return out
+}
+
 func (a *App) renderResponse() {
 	a.renderFooter()
 	v, err := a.g.View("response")
@@ -1941,15 +4497,85 @@ func (a *App) renderResponse() {
 		return
 	}
 	v.Clear()
+	a.resMatches = a.resMatches[:0]
+
+	if a.resErr != nil {
+		fmt.Fprintf(v, "%sno response received: %s%s\n", colorRed, httpclient.ErrorReason(a.resErr), colorReset)
+		fmt.Fprintf(v, "%s %s\n\n", a.lastReq.Method, a.lastReq.URL)
+		fmt.Fprintln(v, a.resErr.Error())
+		return
+	}
 
 	r := a.lastRes
-	fmt.Fprintf(v, "%s\n", colorizeStatus(r.Status))
-	fmt.Fprintf(v, "elapsed: %s\n", r.Elapsed)
+	fmt.Fprintf(v, "%s  %s\n", colorizeStatus(r.Status), protoLabel(r))
+	if a.resCached {
+		fmt.Fprintf(v, "%scached from %s (r: send again)%s\n", colorYellow, r.Started.Format("15:04:05"), colorReset)
+	}
+	fmt.Fprintf(v, "elapsed: %s\n", a.colorizeElapsed(r.Elapsed, r.Elapsed.String()))
 	if ct, ok := r.Headers["content-type"]; ok {
 		fmt.Fprintf(v, "content-type: %s\n", ct)
 	}
+	if loc, ok := r.Headers["location"]; ok && r.StatusCode/100 == 3 {
+		fmt.Fprintf(v, "location: %s\n", loc)
+	}
+	if f := a.jsonFormat; f.MaxDepth > 0 || f.MaxItems > 0 || f.SortKeys || f.Compact {
+		keys := "as received"
+		if f.SortKeys {
+			keys = "sorted"
+		}
+		compact := ""
+		if f.Compact {
+			compact = ", compact"
+		}
+		fmt.Fprintf(v, "%sjson view: depth %s, items %s, keys %s%s%s\n", colorDim, limitLabel(f.MaxDepth), limitLabel(f.MaxItems), keys, compact, colorReset)
+	}
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, r.Body)
+	if a.showDiff && a.prevRes != nil {
+		fmt.Fprintf(v, "diff vs previous run (%s):\n", colorizeStatus(a.prevRes.Status))
+		changes := diffBodies(a.prevRes.Raw, r.Raw)
+		if len(changes) == 0 {
+			fmt.Fprintln(v, colorDim+"(no changes)"+colorReset)
+		}
+		for _, line := range changes {
+			fmt.Fprintln(v, line)
+		}
+		return
+	}
+	a.writeResponseBody(v, a.responseBody())
+}
+
+// colorizeElapsed colors label by how d compares to --warn-slow.
+func (a *App) colorizeElapsed(d time.Duration, label string) string {
+	switch {
+	case a.warnSlow <= 0 || d <= a.warnSlow:
+		return label
+	case d > 2*a.warnSlow:
+		return colorRed + label + " (slow)" + colorReset
+	}
+	return colorYellow + label + " (slow)" + colorReset
+}
+
+// protoLabel shows the protocol the response came over; HTTP/2 and later
+// stand out so a rollout is easy to confirm.
+func protoLabel(r httpclient.Result) string {
+	if r.Proto == "" {
+		return ""
+	}
+	mtls := ""
+	if r.MTLS {
+		mtls = "  " + colorCyan + "mTLS" + colorReset
+	}
+	if r.ProtoMajor >= 2 {
+		return colorGreen + r.Proto + colorReset + mtls
+	}
+	return colorDim + r.Proto + colorReset + mtls
+}
+
+func limitLabel(n int) string {
+	if n <= 0 {
+		return "all"
+	}
+	return strconv.Itoa(n)
 }
 
 func (a *App) selectedKey(viewName string, v *gocui.View) string {
@@ -1960,7 +4586,12 @@ func (a *App) selectedKey(viewName string, v *gocui.View) string {
 	if i < 0 || i >= len(lines) {
 		return ""
 	}
-	line := strings.TrimSpace(lines[i])
+	return lineKey(lines[i])
+}
+
+// lineKey is the param name on a builder row.
+func lineKey(line string) string {
+	line = strings.TrimSpace(line)
 	if line == "" || strings.HasPrefix(line, "(") {
 		return ""
 	}
@@ -1979,6 +4610,8 @@ func (a *App) currentValueFor(key, pane string) string {
 		return a.pathVals[key]
 	case "query":
 		return a.queryVals[key]
+	case "header":
+		return a.headerVals[key]
 	case "body":
 		return a.bodyVals[key]
 	default:
@@ -2058,6 +4691,44 @@ func colorizeStatus(status string) string {
 	return color + status + colorReset
 }
 
+var ansiRe = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// stripANSI removes color escapes. Colors are for the screen only; anything
+// copied, logged or written to a file goes through this first.
+func stripANSI(s string) string {
+	return ansiRe.ReplaceAllString(s, "")
+}
+
+// truncateVisible cuts s to width visible columns, skipping over ANSI color
+// sequences so they don't count toward the width.
+func truncateVisible(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	var b strings.Builder
+	cols := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			j := strings.IndexByte(s[i:], 'm')
+			if j < 0 {
+				break
+			}
+			b.WriteString(s[i : i+j+1])
+			i += j + 1
+			continue
+		}
+		if cols == width {
+			b.WriteString(colorReset)
+			return b.String()
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteRune(r)
+		cols++
+		i += size
+	}
+	return b.String()
+}
+
 func highlightPathParams(path string) string {
 	re := regexp.MustCompile(`\{([^}]+)\}`)
 	return re.ReplaceAllString(path, colorCyan+"{$1}"+colorReset)
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"xhark/internal/httpclient"
+	"xhark/internal/model"
+)
+
+func TestBaseURLWithIPv6AndPorts(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"localhost:3000", "http://localhost:3000"},
+		{"localhost:3000/api", "http://localhost:3000/api"},
+		{"[::1]:8080", "http://[::1]:8080"},
+		{"::1", "http://[::1]"},
+		{"fe80::1%eth0", "http://[fe80::1%25eth0]"},
+		{"https://[::1]:8080/v1", "https://[::1]:8080/v1"},
+		{"http://localhost:3000", "http://localhost:3000"},
+	}
+	for _, tt := range tests {
+		got := normalizeBaseURL(tt.in)
+		if got != tt.want {
+			t.Errorf("normalizeBaseURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if _, err := url.Parse(got); err != nil {
+			t.Errorf("normalizeBaseURL(%q) = %q doesn't parse: %v", tt.in, got, err)
+		}
+
+		doc := &openapi3.T{Servers: openapi3.Servers{{URL: tt.in}}}
+		if got := baseURLFromOpenAPI(doc, ""); got != tt.want {
+			t.Errorf("server %q = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for base, want := range map[string]string{
+		"[::1]:8080":     "http://[::1]:8080/pets/7",
+		"localhost:3000": "http://localhost:3000/pets/7",
+	} {
+		ep := model.Endpoint{
+			Method:     "GET",
+			Path:       "/pets/{id}",
+			PathParams: []model.Param{{Name: "id", Required: true, Type: model.TypeString}},
+		}
+		req, err := httpclient.BuildRequest(nil, normalizeBaseURL(base), ep, map[string]string{"id": "7"}, nil, nil, nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.URL != want {
+			t.Errorf("request URL for %q = %q, want %q", base, req.URL, want)
+		}
+	}
+}
+
+func TestBaseURLFromOpenAPIPathPrefix(t *testing.T) {
+	tests := []struct {
+		server, specURL, want string
+	}{
+		{"https://api.example.com/v2", "", "https://api.example.com/v2"},
+		{"https://api.example.com/v2/", "", "https://api.example.com/v2"},
+		{"/v2", "https://api.example.com/docs/openapi.json", "https://api.example.com/v2"},
+		{"/v2", "spec.yaml", ""},
+	}
+	for _, tt := range tests {
+		doc := &openapi3.T{Servers: openapi3.Servers{{URL: tt.server}}}
+		if got := baseURLFromOpenAPI(doc, tt.specURL); got != tt.want {
+			t.Errorf("server %q (spec %q) = %q, want %q", tt.server, tt.specURL, got, tt.want)
+		}
+	}
+}
+
+func TestStripANSIColorizedJSON(t *testing.T) {
+	body := `{"id":7,"name":"Rex \u001b[31m","tags":["a","b[1]"],"owner":{"ok":true,"score":1.5,"note":null},"empty":{},"none":[]}`
+	var want any
+	if err := json.Unmarshal([]byte(body), &want); err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []httpclient.FormatOptions{{}, {MaxItems: 10}} {
+		colored := httpclient.FormatBody("application/json", []byte(body), opts)
+		if !strings.Contains(colored, "\033[") {
+			t.Fatalf("%+v: body not colorized: %q", opts, colored)
+		}
+		plain := stripANSI(colored)
+		if strings.Contains(plain, "\033[") {
+			t.Errorf("%+v: escapes left in %q", opts, plain)
+		}
+		var got any
+		if err := json.Unmarshal([]byte(plain), &got); err != nil {
+			t.Fatalf("%+v: stripped body isn't JSON: %v\n%s", opts, err, plain)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%+v: got %v, want %v", opts, got, want)
+		}
+	}
+}
+
+func TestAuthForEndpointMultipleAPIKeys(t *testing.T) {
+	a := &App{
+		baseURL: "http://h",
+		secSchemes: map[string]model.SecurityScheme{
+			"headerKey": {Type: "apiKey", In: "header", ParamName: "X-API-Key"},
+			"queryKey":  {Type: "apiKey", In: "query", ParamName: "api_key"},
+			"cookieKey": {Type: "apiKey", In: "cookie", ParamName: "sid"},
+		},
+		authStore: map[string]authState{
+			"headerKey": {schemeName: "headerKey", token: "h1"},
+			"queryKey":  {schemeName: "queryKey", token: "q1"},
+			"cookieKey": {schemeName: "cookieKey", token: "c1"},
+		},
+		activeEndpoint: model.Endpoint{
+			Method:       "GET",
+			Path:         "/pets",
+			QueryParams:  []model.Param{{Name: "api_key", Type: model.TypeString}},
+			HeaderParams: []model.Param{{Name: "Cookie", Type: model.TypeString}},
+			Security:     []model.SecurityRequirement{{"headerKey": nil, "queryKey": nil, "cookieKey": nil}},
+		},
+		queryVals:  map[string]string{"api_key": "typed"},
+		headerVals: map[string]string{"Cookie": "theme=dark"},
+	}
+
+	headers, query, ok := a.authForEndpoint(a.activeEndpoint)
+	if !ok {
+		t.Fatal("requirement not satisfied")
+	}
+	if headers["X-API-Key"] != "h1" || headers["Cookie"] != "sid=c1" || query["api_key"] != "q1" {
+		t.Errorf("headers = %v, query = %v", headers, query)
+	}
+
+	req, err := a.buildActiveRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the key replaces the value typed into the query pane, not doubles it
+	if got := u.Query()["api_key"]; len(got) != 1 || got[0] != "q1" {
+		t.Errorf("api_key = %v, want [q1]", got)
+	}
+	if req.Headers["X-API-Key"] != "h1" {
+		t.Errorf("X-API-Key = %q", req.Headers["X-API-Key"])
+	}
+	if got := req.Headers["Cookie"]; got != "theme=dark; sid=c1" {
+		t.Errorf("Cookie = %q, want the header param's cookie kept next to the key", got)
+	}
+}
+
+func TestAuthForEndpointUnsatisfied(t *testing.T) {
+	a := &App{
+		secSchemes: map[string]model.SecurityScheme{
+			"headerKey": {Type: "apiKey", In: "header", ParamName: "X-API-Key"},
+			"queryKey":  {Type: "apiKey", In: "query", ParamName: "api_key"},
+		},
+		authStore: map[string]authState{"headerKey": {token: "h1"}},
+	}
+	ep := model.Endpoint{Security: []model.SecurityRequirement{{"headerKey": nil, "queryKey": nil}}}
+	if _, _, ok := a.authForEndpoint(ep); ok {
+		t.Error("a requirement with a missing key was applied")
+	}
+}
+
+func TestTruncatedBodyKeepsRunesWhole(t *testing.T) {
+	// "é" and "€" are 2 and 3 bytes; every cut point lands somewhere in them
+	raw := []byte(strings.Repeat("é€", 700))
+	for max := 1024; max < 1030; max++ {
+		a := &App{maxBodyBytes: max, lastRes: httpclient.Result{Raw: raw, Headers: map[string]string{"content-type": "text/plain"}}}
+		body, _, _ := strings.Cut(a.responseBody(), "\n")
+		if !utf8.ValidString(body) {
+			t.Errorf("max %d: truncated body isn't valid UTF-8: %q", max, body[len(body)-4:])
+		}
+		if len(body) > max || len(body) < max-utf8.UTFMax+1 {
+			t.Errorf("max %d: kept %d bytes", max, len(body))
+		}
+	}
+}
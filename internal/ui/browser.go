@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// openInBrowser hands a URL to the platform's default opener.
+func openInBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	// Don't wait: some openers block until the browser exits.
+	return cmd.Start()
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"bytes"
+	"maps"
+
+	"xhark/internal/httpclient"
+)
+
+// cachedResponse returns the latest response in history to a request
+// identical to req: same method, URL, headers and body.
+func (a *App) cachedResponse(req httpclient.RequestSpec) (httpclient.Result, bool) {
+	for i := len(a.history) - 1; i >= 0; i-- {
+		if a.history[i].err != nil {
+			continue
+		}
+		prev := a.history[i].Request
+		if prev.Method == req.Method && prev.URL == req.URL && maps.Equal(prev.Headers, req.Headers) && bytes.Equal(prev.Body, req.Body) {
+			return a.history[i].Response, true
+		}
+	}
+	return httpclient.Result{}, false
+}
+
+// showCached shows a response from history for req without sending it; r
+// on the response screen sends it for real.
+func (a *App) showCached(req httpclient.RequestSpec, res httpclient.Result) {
+	a.errorMsg = ""
+	a.lastReq = req
+	a.setResponse(res)
+	a.resCached = true
+	a.prevRes = nil
+	a.showDiff = false
+	a.scr = screenResponse
+	a.renderResponse()
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands lists the external copy helpers we know about, in the
+// order we try them. The first one found on $PATH wins.
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	default:
+		return [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+	}
+}
+
+// copyToClipboard writes text to the system clipboard via an external helper.
+// Color escapes are stripped so pastes are clean.
+func copyToClipboard(text string) error {
+	text = stripANSI(text)
+	for _, args := range clipboardCommands() {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard helper found (install pbcopy, wl-copy, xclip or xsel)")
+}
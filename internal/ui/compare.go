@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/jroimartin/gocui"
+
+	"xhark/internal/httpclient"
+)
+
+// maxCompareDiff caps the diff panel above the two responses.
+const maxCompareDiff = 8
+
+// markCompare picks the selected history entry as the left side of a
+// comparison, or, with one already picked, compares it with the selection.
+func (a *App) markCompare(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenHistory {
+		return nil
+	}
+	h, ok := a.selectedHistory()
+	if !ok {
+		return nil
+	}
+	if h.err != nil {
+		a.errorMsg = "that request got no response to compare"
+		return nil
+	}
+	if a.compareMark == nil || a.compareMark.seq == h.seq {
+		a.compareMark = &h
+		a.notify("left side picked: press c on another request to compare")
+		return nil
+	}
+	a.cmpLeft, a.cmpRight = *a.compareMark, h
+	a.compareMark = nil
+	a.diffCompare()
+	a.scr = screenCompare
+	a.errorMsg = ""
+	return nil
+}
+
+// diffCompare refreshes the structural diff between the two sides and
+// renders them, once per comparison rather than on every layout.
+func (a *App) diffCompare() {
+	l, r := a.cmpLeft.Response, a.cmpRight.Response
+	a.cmpDiff = nil
+	if l.Status != r.Status {
+		a.cmpDiff = append(a.cmpDiff, colorYellow+"~ status: "+l.Status+" -> "+r.Status+colorReset)
+	}
+	a.cmpDiff = append(a.cmpDiff, diffBodies(l.Raw, r.Raw)...)
+	a.cmpText = [2]string{a.compareSide(l), a.compareSide(r)}
+	if a.g == nil {
+		return
+	}
+	for _, name := range []string{"compare-left", "compare-right"} {
+		if v, err := a.g.View(name); err == nil {
+			v.SetOrigin(0, 0)
+		}
+	}
+}
+
+func (a *App) layoutCompare(maxX, maxY int) error {
+	a.clearMainViews([]string{"compare-diff", "compare-left", "compare-right"})
+
+	// leave the responses at least half the height
+	diffH := min(max(len(a.cmpDiff), 1), maxCompareDiff, max(1, (maxY-8)/2))
+	v, err := a.g.SetView("compare-diff", 0, 2, maxX-1, 3+diffH)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Title = fmt.Sprintf("Diff (%d)", len(a.cmpDiff))
+	v.Clear()
+	if len(a.cmpDiff) == 0 {
+		fmt.Fprintln(v, colorDim+"(no differences)"+colorReset)
+	}
+	width, _ := v.Size()
+	for i, line := range a.cmpDiff {
+		if i == diffH-1 && len(a.cmpDiff) > diffH {
+			fmt.Fprintf(v, "%s... %d more%s\n", colorDim, len(a.cmpDiff)-i, colorReset)
+			break
+		}
+		fmt.Fprintln(v, truncateVisible(line, width))
+	}
+
+	top := 3 + diffH
+	mid := maxX / 2
+	sides := []struct {
+		name string
+		x0   int
+		x1   int
+		h    historyEntry
+		text string
+	}{
+		{"compare-left", 0, mid - 1, a.cmpLeft, a.cmpText[0]},
+		{"compare-right", mid, maxX - 1, a.cmpRight, a.cmpText[1]},
+	}
+	for _, s := range sides {
+		v, err := a.g.SetView(s.name, s.x0, top, s.x1, maxY-3)
+		if err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		p := s.h.Request.URL
+		if u, err := url.Parse(p); err == nil {
+			p = u.RequestURI()
+		}
+		v.Title = s.h.Request.Method + " " + p
+		v.Clear()
+		fmt.Fprint(v, s.text)
+	}
+	if _, err := a.g.SetCurrentView("compare-left"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// compareSide renders one side of the comparison: status, timing and body.
+func (a *App) compareSide(r httpclient.Result) string {
+	return fmt.Sprintf("%s  %s\n%s%s%s\n\n", colorizeStatus(r.Status), a.colorizeElapsed(r.Elapsed, r.Elapsed.String()),
+		colorDim, r.Started.Format("15:04:05"), colorReset) +
+		httpclient.FormatBody(r.Headers["content-type"], r.Raw, a.jsonFormat)
+}
+
+// scrollCompare scrolls both responses together.
+func (a *App) scrollCompare(delta int) func(*gocui.Gui, *gocui.View) error {
+	scroll := a.scrollView(delta)
+	return func(g *gocui.Gui, _ *gocui.View) error {
+		if a.scr != screenCompare {
+			return nil
+		}
+		for _, name := range []string{"compare-left", "compare-right"} {
+			if v, err := g.View(name); err == nil {
+				scroll(g, v)
+			}
+		}
+		return nil
+	}
+}
+
+// rerunCompare sends both requests again and compares the fresh responses.
+func (a *App) rerunCompare(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenCompare || a.comparing {
+		return nil
+	}
+	a.comparing = true
+	a.errorMsg = ""
+	a.notify("sending both requests...")
+	reqs := []httpclient.RequestSpec{a.cmpLeft.Request, a.cmpRight.Request}
+	go func() {
+		res := make([]httpclient.Result, len(reqs))
+		errs := make([]error, len(reqs))
+		for i, req := range reqs {
+			ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout(req.Method))
+			res[i], errs[i] = httpclient.Execute(ctx, &a.client, req)
+			cancel()
+		}
+		a.g.Update(func(*gocui.Gui) error {
+			a.comparing = false
+			for i, req := range reqs {
+				a.logExchange(req, res[i], errs[i])
+			}
+			if err := errors.Join(errs...); err != nil {
+				a.errorMsg = err.Error()
+				return nil
+			}
+			a.cmpLeft.Response, a.cmpRight.Response = res[0], res[1]
+			a.diffCompare()
+			a.notify("compared fresh responses")
+			return nil
+		})
+	}()
+	return nil
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"xhark/internal/httpclient"
+)
+
+func TestCompareMarkSurvivesHistoryTrim(t *testing.T) {
+	a := &App{scr: screenHistory}
+	record := func(n int) {
+		req := httpclient.RequestSpec{Method: "GET", URL: fmt.Sprintf("http://h/items/%d", n)}
+		res := httpclient.Result{Status: "200 OK", StatusCode: 200, Headers: map[string]string{"content-type": "application/json"}, Raw: []byte(fmt.Sprintf(`{"n": %d}`, n))}
+		a.recordHistory(a.newHistoryEntry(req), res, nil)
+	}
+	for n := range maxHistory {
+		record(n)
+	}
+
+	// pick the newest as the left side, then more requests trim the oldest
+	a.historySel = 0
+	if err := a.markCompare(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	record(maxHistory)
+	record(maxHistory + 1)
+
+	a.historySel = 0
+	if err := a.markCompare(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if a.scr != screenCompare {
+		t.Fatal("no comparison opened")
+	}
+	if got := a.cmpLeft.Request.URL; got != fmt.Sprintf("http://h/items/%d", maxHistory-1) {
+		t.Errorf("left side is %s, want the request picked", got)
+	}
+	if !strings.Contains(a.cmpText[0], `"n"`) || !strings.Contains(a.cmpText[1], fmt.Sprint(maxHistory+1)) {
+		t.Errorf("sides not rendered: %q / %q", a.cmpText[0], a.cmpText[1])
+	}
+}
@@ -0,0 +1,286 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+
+	"xhark/internal/curl"
+	"xhark/internal/httpclient"
+	"xhark/internal/model"
+)
+
+// promptCurlImport asks for a curl command to load into the builder.
+func (a *App) promptCurlImport(g *gocui.Gui, v *gocui.View) error {
+	if a.scr != screenEndpoints || a.editing {
+		return nil
+	}
+	return a.openEditModal(g, "paste curl command", "", "curl:import")
+}
+
+// importCurl pre-fills the builder from a curl command when it matches an
+// endpoint (by method and path template); otherwise it sends it as-is.
+func (a *App) importCurl(cmdline string) {
+	if strings.TrimSpace(cmdline) == "" {
+		return
+	}
+	cmd, err := curl.Parse(cmdline)
+	if err != nil {
+		a.errorMsg = err.Error()
+		return
+	}
+	u, err := url.Parse(cmd.URL)
+	if err != nil {
+		a.errorMsg = "curl: " + err.Error()
+		return
+	}
+
+	ep, pathVals, ok := matchEndpoint(a.endpoints, cmd.Method, u.EscapedPath())
+	if !ok {
+		a.activeEndpoint = model.Endpoint{}
+		a.send(httpclient.RequestSpec{Method: cmd.Method, URL: cmd.URL, Headers: cmd.Headers, Body: []byte(cmd.Body)}, false)
+		a.notify("no matching endpoint; sent the curl request as-is")
+		return
+	}
+
+	a.startBuilder(ep)
+	a.pathVals = pathVals
+	q := u.Query()
+	for _, p := range ep.QueryParams {
+		if vs, ok := q[p.Name]; ok && len(vs) > 0 {
+			a.queryVals[p.Name] = vs[0]
+		}
+	}
+	creds, authHeaders := a.curlCredentials(ep, cmd.Headers, q)
+	var dropped []string
+	for k, v := range cmd.Headers {
+		if authHeaders[strings.ToLower(k)] {
+			continue
+		}
+		declared := false
+		for _, p := range ep.HeaderParams {
+			if strings.EqualFold(k, p.Name) {
+				a.headerVals[p.Name] = v
+				declared = true
+			}
+		}
+		if !declared && !derivedHeaders[strings.ToLower(k)] {
+			dropped = append(dropped, k)
+		}
+	}
+	if isTextBody(ep) || ep.Body != nil && json.Valid([]byte(cmd.Body)) {
+		a.bodyRaw = cmd.Body
+	}
+	msg := "imported curl into " + ep.Method + " " + ep.Path
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		msg += "; not in the spec, dropped " + strings.Join(dropped, ", ")
+	}
+	a.notify(msg)
+	a.importCredentials(creds)
+}
+
+// derivedHeaders are set from the endpoint and the defaults on every
+// request, so a curl command carrying them loses nothing when they're
+// dropped.
+var derivedHeaders = map[string]bool{
+	"accept":          true,
+	"accept-encoding": true,
+	"content-length":  true,
+	"content-type":    true,
+	"host":            true,
+	"user-agent":      true,
+}
+
+// curlCredentials picks out of a curl command the credentials for the
+// endpoint's security schemes, so the request can be replayed as the same
+// user: a bearer Authorization for a bearer or oauth2 scheme, and an apiKey
+// from its header, cookie or query parameter. authHeaders lists (lowercased)
+// the headers they came from.
+func (a *App) curlCredentials(ep model.Endpoint, headers map[string]string, query url.Values) (creds map[string]string, authHeaders map[string]bool) {
+	creds, authHeaders = map[string]string{}, map[string]bool{}
+	header := func(name string) (string, bool) {
+		for k, v := range headers {
+			if strings.EqualFold(k, name) {
+				return v, true
+			}
+		}
+		return "", false
+	}
+	for _, req := range ep.Security {
+		for name := range req {
+			ss, ok := a.secSchemes[name]
+			if !ok {
+				continue
+			}
+			switch {
+			case ss.Type == "oauth2" || strings.EqualFold(ss.Scheme, "bearer"):
+				v, _ := header("Authorization")
+				if t, ok := strings.CutPrefix(v, "Bearer "); ok && strings.TrimSpace(t) != "" {
+					creds[name] = strings.TrimSpace(t)
+					authHeaders["authorization"] = true
+				}
+			case ss.Type == "apiKey" && ss.In == "query":
+				if v := query.Get(ss.ParamName); v != "" {
+					creds[name] = v
+				}
+			case ss.Type == "apiKey" && ss.In == "cookie":
+				v, _ := header("Cookie")
+				for _, c := range strings.Split(v, ";") {
+					if k, val, ok := strings.Cut(strings.TrimSpace(c), "="); ok && k == ss.ParamName && val != "" {
+						creds[name] = val
+						authHeaders["cookie"] = true
+					}
+				}
+			case ss.Type == "apiKey":
+				if v, ok := header(ss.ParamName); ok && strings.TrimSpace(v) != "" {
+					creds[name] = strings.TrimSpace(v)
+					authHeaders[strings.ToLower(ss.ParamName)] = true
+				}
+			}
+		}
+	}
+	return creds, authHeaders
+}
+
+// importCredentials stores the credentials taken from a curl command. The
+// stored ones are persisted, so replacing a different token already there
+// is asked first.
+func (a *App) importCredentials(creds map[string]string) {
+	var replace []string
+	stored := false
+	for name, token := range creds {
+		if st, ok := a.authStore[name]; ok && strings.TrimSpace(st.token) != "" && st.token != token {
+			replace = append(replace, name)
+			continue
+		}
+		a.storeCredential(name, token)
+		stored = true
+	}
+	if stored {
+		a.saveAuthTokens()
+	}
+	if len(replace) == 0 {
+		return
+	}
+	sort.Strings(replace)
+	a.openPicker(&picker{
+		title: "curl has a different token for " + strings.Join(replace, ", "),
+		items: []string{"keep the stored token", "use the token from curl"},
+		onPick: func(i int) error {
+			if i == 1 {
+				for _, name := range replace {
+					a.storeCredential(name, creds[name])
+				}
+				a.saveAuthTokens()
+				a.notify("token from curl saved for " + strings.Join(replace, ", "))
+			}
+			return nil
+		},
+	})
+}
+
+func (a *App) storeCredential(name, token string) {
+	st := authState{schemeName: name, token: token, acquiredAt: time.Now()}
+	if a.secSchemes[name].Type != "apiKey" {
+		st.tokenType = "Bearer"
+	}
+	a.authStore[name] = st
+}
+
+// matchEndpoint finds the endpoint whose method and path template match a
+// request path. The path may carry a base path prefix ("/api/v1/users/42"
+// matches "/users/{id}"). The most literal template wins.
+func matchEndpoint(eps []model.Endpoint, method, path string) (model.Endpoint, map[string]string, bool) {
+	var best model.Endpoint
+	var bestVals map[string]string
+	bestParams, bestLen := -1, -1
+	for _, ep := range eps {
+		if !strings.EqualFold(ep.Method, method) {
+			continue
+		}
+		names := pathParamRe.FindAllStringSubmatch(ep.Path, -1)
+		m := templateRegexp(ep.Path).FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		if bestParams >= 0 && (len(names) > bestParams || len(names) == bestParams && len(ep.Path) <= bestLen) {
+			continue
+		}
+		vals := map[string]string{}
+		for i, n := range names {
+			v, err := url.PathUnescape(m[i+1])
+			if err != nil {
+				v = m[i+1]
+			}
+			vals[n[1]] = v
+		}
+		best, bestVals, bestParams, bestLen = ep, vals, len(names), len(ep.Path)
+	}
+	return best, bestVals, bestParams >= 0
+}
+
+var pathParamRe = regexp.MustCompile(`\{([^}]+)\}`)
+
+// templateRegexp matches a path template, each {param} capturing one segment.
+func templateRegexp(tpl string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^.*?")
+	last := 0
+	for _, loc := range pathParamRe.FindAllStringIndex(tpl, -1) {
+		b.WriteString(regexp.QuoteMeta(tpl[last:loc[0]]))
+		b.WriteString("([^/]+)")
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(tpl[last:]))
+	b.WriteString("/?$")
+	return regexp.MustCompile(b.String())
+}
+
+// copyAsCurl copies the request as a curl command: on the response screen
+// the one that was sent, in the builder the one the current params build.
+func (a *App) copyAsCurl(*gocui.Gui, *gocui.View) error {
+	if a.editing {
+		return nil
+	}
+	var req httpclient.RequestSpec
+	switch a.scr {
+	case screenResponse:
+		req = a.lastReq
+	case screenBuilder:
+		if msg := a.requireBaseURL(); msg != "" {
+			a.errorMsg = msg
+			return nil
+		}
+		r, err := a.buildActiveRequest()
+		if err != nil {
+			a.errorMsg = err.Error()
+			return nil
+		}
+		req = r
+	default:
+		return nil
+	}
+	if req.URL == "" {
+		return nil
+	}
+	cmd := curl.Command{Method: req.Method, URL: req.URL, Headers: map[string]string{}, Body: string(req.Body)}
+	for k, v := range req.Headers {
+		// Execute doesn't send empty headers either
+		if strings.TrimSpace(v) != "" {
+			cmd.Headers[k] = v
+		}
+	}
+	if err := copyToClipboard(cmd.String()); err != nil {
+		a.errorMsg = err.Error()
+		return nil
+	}
+	a.errorMsg = ""
+	a.notify("copied request as curl")
+	return nil
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"xhark/internal/model"
+)
+
+func curlTestApp() *App {
+	return &App{
+		endpoints: []model.Endpoint{{
+			Method:       "GET",
+			Path:         "/pets/{id}",
+			HeaderParams: []model.Param{{Name: "X-Request-Id"}},
+			Security:     []model.SecurityRequirement{{"bearer": nil, "key": nil}},
+		}},
+		secSchemes: map[string]model.SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer"},
+			"key":    {Type: "apiKey", In: "header", ParamName: "X-API-Key"},
+		},
+		authStore: map[string]authState{},
+	}
+}
+
+func TestImportCurlMultiLine(t *testing.T) {
+	a := curlTestApp()
+	lines := []string{
+		`curl 'https://api.example.com/pets/7' \`,
+		`  -H 'Authorization: Bearer abc' \`,
+		`  -H 'X-API-Key: k1' \`,
+		`  -H 'x-request-id: r1' \`,
+		`  -H 'X-Debug: 1'`,
+	}
+	a.importCurl(strings.Join(lines, "\n"))
+
+	if a.activeEndpoint.Path != "/pets/{id}" || a.pathVals["id"] != "7" {
+		t.Fatalf("builder on %s with id %q", a.activeEndpoint.Path, a.pathVals["id"])
+	}
+	if got := a.headerVals["X-Request-Id"]; got != "r1" {
+		t.Errorf("X-Request-Id = %q, want r1", got)
+	}
+	if len(a.headerVals) != 1 {
+		t.Errorf("header params = %v, want only X-Request-Id", a.headerVals)
+	}
+	if a.authStore["bearer"].token != "abc" || a.authStore["key"].token != "k1" {
+		t.Errorf("auth store = %+v", a.authStore)
+	}
+	if !strings.Contains(a.toast, "dropped X-Debug") {
+		t.Errorf("toast = %q, want the undeclared header named", a.toast)
+	}
+}
+
+func TestImportCurlAsksBeforeReplacingToken(t *testing.T) {
+	a := curlTestApp()
+	a.authStore["bearer"] = authState{schemeName: "bearer", tokenType: "Bearer", token: "old"}
+	a.importCurl(`curl https://api.example.com/pets/7 -H 'Authorization: Bearer new'`)
+
+	if a.authStore["bearer"].token != "old" {
+		t.Fatal("the stored token was replaced without asking")
+	}
+	if a.picker == nil {
+		t.Fatal("no confirmation asked")
+	}
+	if err := a.picker.onPick(1); err != nil {
+		t.Fatal(err)
+	}
+	if st := a.authStore["bearer"]; st.token != "new" || st.tokenType != "Bearer" {
+		t.Errorf("after confirming, stored = %+v", st)
+	}
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffBodies describes what changed between two response bodies. JSON bodies
+// are compared structurally (added/removed/changed fields by path); anything
+// else falls back to a line diff.
+func diffBodies(prev, cur []byte) []string {
+	var pv, cv any
+	if json.Unmarshal(prev, &pv) == nil && json.Unmarshal(cur, &cv) == nil {
+		var out []string
+		diffJSON("$", pv, cv, &out)
+		return out
+	}
+	return diffLines(string(prev), string(cur))
+}
+
+func diffJSON(path string, prev, cur any, out *[]string) {
+	switch p := prev.(type) {
+	case map[string]any:
+		c, ok := cur.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := map[string]bool{}
+		for k := range p {
+			keys[k] = true
+		}
+		for k := range c {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			pv, inPrev := p[k]
+			cv, inCur := c[k]
+			sub := path + "." + k
+			switch {
+			case !inPrev:
+				*out = append(*out, colorGreen+"+ "+sub+": "+compactJSON(cv)+colorReset)
+			case !inCur:
+				*out = append(*out, colorRed+"- "+sub+": "+compactJSON(pv)+colorReset)
+			default:
+				diffJSON(sub, pv, cv, out)
+			}
+		}
+		return
+	case []any:
+		c, ok := cur.([]any)
+		if !ok {
+			break
+		}
+		n := len(p)
+		if len(c) > n {
+			n = len(c)
+		}
+		for i := 0; i < n; i++ {
+			sub := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(p):
+				*out = append(*out, colorGreen+"+ "+sub+": "+compactJSON(c[i])+colorReset)
+			case i >= len(c):
+				*out = append(*out, colorRed+"- "+sub+": "+compactJSON(p[i])+colorReset)
+			default:
+				diffJSON(sub, p[i], c[i], out)
+			}
+		}
+		return
+	}
+	a, b := compactJSON(prev), compactJSON(cur)
+	if a != b {
+		*out = append(*out, colorYellow+"~ "+path+": "+a+" -> "+b+colorReset)
+	}
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// diffLines is a minimal LCS line diff; good enough for typical API payloads.
+func diffLines(prev, cur string) []string {
+	a := strings.Split(prev, "\n")
+	b := strings.Split(cur, "\n")
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, colorRed+"- "+a[i]+colorReset)
+			i++
+		default:
+			out = append(out, colorGreen+"+ "+b[j]+colorReset)
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, colorRed+"- "+a[i]+colorReset)
+	}
+	for ; j < len(b); j++ {
+		out = append(out, colorGreen+"+ "+b[j]+colorReset)
+	}
+	return out
+}
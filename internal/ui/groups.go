@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+)
+
+// untaggedGroup heads the endpoints without tags in the grouped list.
+const untaggedGroup = "untagged"
+
+// endpointRow is a line of the grouped endpoint list: a tag header (idx -1)
+// or an endpoint beneath it.
+type endpointRow struct {
+	tag   string
+	idx   int
+	count int // endpoints in the group, for headers
+}
+
+// endpointTag is the group an endpoint is listed under: its first tag, so
+// an endpoint with several tags shows up once.
+func endpointTag(tags []string) string {
+	if len(tags) == 0 || tags[0] == "" {
+		return untaggedGroup
+	}
+	return tags[0]
+}
+
+// groupRows rebuilds the rows of the grouped list, if it's on, and keeps
+// the cursor within the list. Grouped, the list is longer than the filtered
+// endpoints by its headers, so this is the only place to clamp the cursor.
+func (a *App) groupRows() {
+	a.rows = a.rows[:0]
+	if a.grouped {
+		a.layoutGroups()
+	}
+	if a.selected >= a.listLen() {
+		a.selected = 0
+	}
+}
+
+// layoutGroups lays the filtered endpoints out under their tag headers.
+// Groups keep the order their first endpoint has in the filtered list, so the
+// best filter match's group comes first; collapsed groups keep only the
+// header.
+func (a *App) layoutGroups() {
+	var order []string
+	byTag := map[string][]int{}
+	for _, idx := range a.filtered {
+		tag := endpointTag(a.endpoints[idx].Tags)
+		if _, ok := byTag[tag]; !ok {
+			order = append(order, tag)
+		}
+		byTag[tag] = append(byTag[tag], idx)
+	}
+	for _, tag := range order {
+		a.rows = append(a.rows, endpointRow{tag: tag, idx: -1, count: len(byTag[tag])})
+		if a.collapsed[tag] {
+			continue
+		}
+		for _, idx := range byTag[tag] {
+			a.rows = append(a.rows, endpointRow{tag: tag, idx: idx})
+		}
+	}
+}
+
+// listLen is the number of selectable lines in the endpoint list.
+func (a *App) listLen() int {
+	if a.grouped {
+		return len(a.rows)
+	}
+	return len(a.filtered)
+}
+
+// selectedEndpoint returns the endpoint index under the cursor; false on a
+// group header or an empty list.
+func (a *App) selectedEndpoint() (int, bool) {
+	if a.grouped {
+		if a.selected >= len(a.rows) || a.rows[a.selected].idx < 0 {
+			return 0, false
+		}
+		return a.rows[a.selected].idx, true
+	}
+	if a.selected >= len(a.filtered) {
+		return 0, false
+	}
+	return a.filtered[a.selected], true
+}
+
+// selectEndpointIdx puts the cursor on endpoint idx, if it's listed.
+func (a *App) selectEndpointIdx(idx int) {
+	if a.grouped {
+		for i, r := range a.rows {
+			if r.idx == idx {
+				a.selected = i
+				return
+			}
+		}
+		return
+	}
+	for i, fi := range a.filtered {
+		if fi == idx {
+			a.selected = i
+			return
+		}
+	}
+}
+
+// nthEndpointRow is the list position of the n-th (0-based) endpoint,
+// skipping group headers; -1 if there are fewer.
+func (a *App) nthEndpointRow(n int) int {
+	if !a.grouped {
+		if n < len(a.filtered) {
+			return n
+		}
+		return -1
+	}
+	for i, r := range a.rows {
+		if r.idx < 0 {
+			continue
+		}
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return -1
+}
+
+func (a *App) toggleGrouped(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints {
+		return nil
+	}
+	cur, ok := a.selectedEndpoint()
+	a.grouped = !a.grouped
+	a.selected = 0
+	a.groupRows()
+	if ok {
+		a.selectEndpointIdx(cur)
+	}
+	a.renderEndpoints()
+	return nil
+}
+
+// toggleGroup collapses or expands the group under the cursor (enter on a
+// header).
+func (a *App) toggleGroup() {
+	tag := a.rows[a.selected].tag
+	if a.collapsed == nil {
+		a.collapsed = map[string]bool{}
+	}
+	a.collapsed[tag] = !a.collapsed[tag]
+	a.groupRows()
+	for i, r := range a.rows {
+		if r.idx < 0 && r.tag == tag {
+			a.selected = i
+			break
+		}
+	}
+	a.renderEndpoints()
+}
+
+// groupHeader renders a tag header line of the grouped list.
+func (a *App) groupHeader(r endpointRow) string {
+	marker := "v"
+	if a.collapsed[r.tag] {
+		marker = ">"
+	}
+	return fmt.Sprintf("%s %s%s%s %s(%d)%s", marker, colorCyan, r.tag, colorReset, colorDim, r.count, colorReset)
+}
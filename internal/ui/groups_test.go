@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+
+	"xhark/internal/model"
+)
+
+func TestGroupedSelectionSurvivesRefilter(t *testing.T) {
+	a := &App{
+		endpoints: []model.Endpoint{
+			{Method: "GET", Path: "/pets", Tags: []string{"pets"}},
+			{Method: "POST", Path: "/pets", Tags: []string{"pets"}},
+			{Method: "GET", Path: "/users", Tags: []string{"users"}},
+		},
+		grouped: true,
+	}
+	a.recomputeFilter()
+	// pets header, 2 pets, users header, GET /users: more rows than endpoints
+	if a.listLen() != 5 {
+		t.Fatalf("listLen = %d, want 5", a.listLen())
+	}
+	a.selected = 4
+	a.recomputeFilter()
+	if idx, ok := a.selectedEndpoint(); !ok || a.endpoints[idx].Path != "/users" {
+		t.Errorf("selected row %d, want GET /users kept under the cursor", a.selected)
+	}
+
+	a.filter = "pets"
+	a.recomputeFilter()
+	if a.selected >= a.listLen() {
+		t.Errorf("selected = %d past the %d rows", a.selected, a.listLen())
+	}
+}
@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jroimartin/gocui"
+
+	"xhark/internal/har"
+	"xhark/internal/httpclient"
+	"xhark/internal/model"
+)
+
+// maxHistory caps how many sent requests we keep for the history screen and
+// HAR export; older ones are dropped first.
+const maxHistory = 200
+
+// historyEntry is a sent request and its response, plus the builder state it
+// was sent from so it can be reopened. ep is zero for requests that didn't
+// come from the builder (e.g. an unmatched curl import). err is set, and
+// Response empty but for Started, when no response came back.
+type historyEntry struct {
+	har.Entry
+	err error
+	// seq numbers entries as they're recorded, identifying one while the
+	// history is trimmed under it.
+	seq int
+
+	ep         model.Endpoint
+	pathVals   map[string]string
+	queryVals  map[string]string
+	headerVals map[string]string
+	bodyVals   map[string]string
+	bodyRaw    string
+}
+
+// newHistoryEntry captures req with the builder state it's being sent from.
+// It's taken when the request goes out: the builder may change while the
+// request is in flight.
+func (a *App) newHistoryEntry(req httpclient.RequestSpec) historyEntry {
+	return historyEntry{
+		Entry:      har.Entry{Request: req, Response: httpclient.Result{Started: time.Now()}},
+		ep:         a.activeEndpoint,
+		pathVals:   copyVals(a.pathVals),
+		queryVals:  copyVals(a.queryVals),
+		headerVals: copyVals(a.headerVals),
+		bodyVals:   copyVals(a.bodyVals),
+		bodyRaw:    a.bodyRaw,
+	}
+}
+
+// recordHistory completes h with what came back and adds it to the history.
+func (a *App) recordHistory(h historyEntry, res httpclient.Result, err error) {
+	if err != nil {
+		h.err = err
+	} else {
+		h.Response = res
+	}
+	a.historySeq++
+	h.seq = a.historySeq
+	a.history = append(a.history, h)
+	if len(a.history) > maxHistory {
+		a.history = a.history[len(a.history)-maxHistory:]
+	}
+}
+
+// selectedHistory returns the entry selected on the history screen; rows
+// are listed newest first.
+func (a *App) selectedHistory() (historyEntry, bool) {
+	i := len(a.history) - 1 - a.historySel
+	if i < 0 || i >= len(a.history) {
+		return historyEntry{}, false
+	}
+	return a.history[i], true
+}
+
+func (a *App) openHistory(*gocui.Gui, *gocui.View) error {
+	if a.editing || a.authOpen || a.picker != nil || a.scr == screenHistory {
+		return nil
+	}
+	if len(a.history) == 0 {
+		a.errorMsg = "no requests sent yet"
+		return nil
+	}
+	a.historyFrom = a.scr
+	a.historySel = 0
+	a.compareMark = nil
+	a.scr = screenHistory
+	a.errorMsg = ""
+	return nil
+}
+
+func (a *App) layoutHistory(maxX, maxY int) error {
+	a.clearMainViews([]string{"history"})
+
+	v, err := a.g.SetView("history", 0, 2, maxX-1, maxY-3)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Highlight = true
+		v.SelFgColor = gocui.ColorBlack
+		v.SelBgColor = gocui.ColorGreen
+		v.Autoscroll = false
+	}
+	v.Title = fmt.Sprintf("History (%d)", len(a.history))
+	a.renderHistory(v)
+	if _, err := a.g.SetCurrentView("history"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *App) renderHistory(v *gocui.View) {
+	v.Clear()
+	width, height := v.Size()
+	for i := len(a.history) - 1; i >= 0; i-- {
+		h := a.history[i]
+		p := h.Request.URL
+		if u, err := url.Parse(h.Request.URL); err == nil {
+			p = u.RequestURI()
+		}
+		status := colorizeStatus(strconv.Itoa(h.Response.StatusCode))
+		elapsed := a.historyElapsed(h.Response.Elapsed)
+		if h.err != nil {
+			status = colorRed + "ERR" + colorReset
+			elapsed = padRight("", 8)
+			p += "  " + colorRed + h.err.Error() + colorReset
+		}
+		line := fmt.Sprintf("%s  %s  %s  %s  %s",
+			h.Response.Started.Format("15:04:05"),
+			colorizeMethod(h.Request.Method),
+			status,
+			elapsed,
+			p)
+		if a.compareMark != nil {
+			mark := "  "
+			if h.seq == a.compareMark.seq {
+				mark = colorCyan + "L " + colorReset
+			}
+			line = mark + line
+		}
+		fmt.Fprintln(v, truncateVisible(line, width))
+	}
+	// keep the selection in view
+	_, oy := v.Origin()
+	if a.historySel < oy {
+		oy = a.historySel
+	} else if height > 0 && a.historySel >= oy+height {
+		oy = a.historySel - height + 1
+	}
+	v.SetOrigin(0, oy)
+	v.SetCursor(0, a.historySel-oy)
+}
+
+func (a *App) moveHistorySel(delta int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if a.scr != screenHistory {
+			return nil
+		}
+		a.historySel = max(0, min(a.historySel+delta, len(a.history)-1))
+		return nil
+	}
+}
+
+// restoreHistory puts the builder back in the state entry h was sent from.
+func (a *App) restoreHistory(h historyEntry) {
+	a.startBuilder(h.ep)
+	a.pathVals = copyVals(h.pathVals)
+	a.queryVals = copyVals(h.queryVals)
+	a.headerVals = copyVals(h.headerVals)
+	a.bodyVals = copyVals(h.bodyVals)
+	a.bodyRaw = h.bodyRaw
+}
+
+// reopenHistory loads the selected request back into the builder, to tweak
+// and send again.
+func (a *App) reopenHistory(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenHistory {
+		return nil
+	}
+	h, ok := a.selectedHistory()
+	if !ok {
+		return nil
+	}
+	if h.ep.Method == "" {
+		a.errorMsg = "this request wasn't sent from the builder (press r to rerun it)"
+		return nil
+	}
+	a.restoreHistory(h)
+	return nil
+}
+
+// rerunHistory sends the selected request again exactly as it was sent.
+func (a *App) rerunHistory(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenHistory {
+		return nil
+	}
+	h, ok := a.selectedHistory()
+	if !ok {
+		return nil
+	}
+	if h.ep.Method != "" {
+		a.restoreHistory(h)
+	} else {
+		a.activeEndpoint = model.Endpoint{}
+	}
+	a.send(h.Request, false)
+	return nil
+}
+
+func (a *App) historyElapsed(d time.Duration) string {
+	label := padRight(d.Round(time.Millisecond).String(), 8)
+	if a.warnSlow > 0 && d > a.warnSlow {
+		color := colorYellow
+		if d > 2*a.warnSlow {
+			color = colorRed
+		}
+		return color + label + colorReset
+	}
+	return colorDim + label + colorReset
+}
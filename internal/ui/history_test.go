@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"xhark/internal/httpclient"
+	"xhark/internal/model"
+)
+
+func TestHistoryEntryCapturedAtSend(t *testing.T) {
+	a := &App{
+		activeEndpoint: model.Endpoint{Method: "GET", Path: "/pets/{id}"},
+		pathVals:       map[string]string{"id": "1"},
+	}
+	req := httpclient.RequestSpec{Method: "GET", URL: "http://h/pets/1"}
+	entry := a.newHistoryEntry(req)
+
+	// the user moves on while the request is in flight
+	a.activeEndpoint = model.Endpoint{Method: "POST", Path: "/users"}
+	a.pathVals["id"] = "2"
+
+	a.recordHistory(entry, httpclient.Result{StatusCode: 200}, nil)
+	h := a.history[0]
+	if h.ep.Path != "/pets/{id}" || h.pathVals["id"] != "1" {
+		t.Errorf("entry has endpoint %s with id %q, want the state it was sent from", h.ep.Path, h.pathVals["id"])
+	}
+	if h.Response.StatusCode != 200 {
+		t.Errorf("status = %d, want 200", h.Response.StatusCode)
+	}
+}
+
+func TestFailedRequestRecorded(t *testing.T) {
+	a := &App{}
+	entry := a.newHistoryEntry(httpclient.RequestSpec{Method: "GET", URL: "http://h/x"})
+	a.recordHistory(entry, httpclient.Result{}, errors.New("connection refused"))
+	if len(a.history) != 1 || a.history[0].err == nil {
+		t.Fatalf("history = %+v, want one failed entry", a.history)
+	}
+	if a.history[0].Response.Started.IsZero() {
+		t.Error("failed entry has no time")
+	}
+	if _, ok := a.cachedResponse(entry.Request); ok {
+		t.Error("a failed request was served from the cache")
+	}
+}
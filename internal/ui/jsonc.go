@@ -0,0 +1,71 @@
+package ui
+
+// stripJSONC turns commented JSON (JSONC) into strict JSON: // and /* */
+// comments and trailing commas are blanked out. Comments are replaced with
+// spaces (newlines are kept) so error offsets still point at the right place.
+func stripJSONC(s string) string {
+	b := []byte(s)
+	inString := false
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(b) && b[i+1] == '/':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case c == '/' && i+1 < len(b) && b[i+1] == '*':
+			b[i], b[i+1] = ' ', ' '
+			i += 2
+			for ; i < len(b); i++ {
+				if b[i] == '*' && i+1 < len(b) && b[i+1] == '/' {
+					b[i], b[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+		case c == ',':
+			if isTrailingComma(b, i+1) {
+				b[i] = ' '
+			}
+		}
+	}
+	return string(b)
+}
+
+// isTrailingComma reports whether only whitespace and comments separate the
+// comma ending at i from a closing bracket.
+func isTrailingComma(b []byte, i int) bool {
+	for i < len(b) {
+		switch {
+		case b[i] == ' ' || b[i] == '\t' || b[i] == '\n' || b[i] == '\r':
+			i++
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for i < len(b) && b[i] != '\n' {
+				i++
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			i += 2
+			for i+1 < len(b) && !(b[i] == '*' && b[i+1] == '/') {
+				i++
+			}
+			i += 2
+		default:
+			return b[i] == '}' || b[i] == ']'
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/jroimartin/gocui"
+
+	"xhark/internal/model"
+)
+
+// layoutTestApp returns an app on a Gui that has never been started: views
+// can be laid out without a terminal, only drawing needs one.
+func layoutTestApp() *App {
+	a := NewApp(nil, nil)
+	a.g = &gocui.Gui{}
+	a.endpoints = []model.Endpoint{{
+		Method:      "GET",
+		Path:        "/pets/{id}",
+		Summary:     "Get a pet",
+		PathParams:  []model.Param{{Name: "id", Type: model.TypeString, Required: true}},
+		QueryParams: []model.Param{{Name: "verbose", Type: model.TypeBoolean}},
+		Security:    []model.SecurityRequirement{{"bearer": nil}},
+	}}
+	a.secSchemes = map[string]model.SecurityScheme{"bearer": {Type: "http", Scheme: "bearer"}}
+	a.filterEndpoints()
+	return a
+}
+
+func TestLayoutSizes(t *testing.T) {
+	sizes := [][2]int{{20, 5}, {minLayoutWidth, minLayoutHeight}, {120, 40}}
+	for _, scr := range []screen{screenEndpoints, screenBuilder} {
+		for _, modal := range []string{"", "auth", "edit"} {
+			for _, size := range sizes {
+				a := layoutTestApp()
+				if scr == screenBuilder {
+					a.startBuilder(a.endpoints[0])
+				}
+				// lay out once at full size so the modal opens as it would
+				// in use, then shrink
+				if err := a.layoutSize(120, 40); err != nil {
+					t.Fatalf("screen %d at 120x40: %v", scr, err)
+				}
+				switch modal {
+				case "auth":
+					a.authOpen = true
+				case "edit":
+					if err := a.openEditModal(a.g, "value", "x", "query:verbose"); err != nil {
+						t.Fatalf("open edit: %v", err)
+					}
+				}
+				if err := a.layoutSize(size[0], size[1]); err != nil {
+					t.Errorf("screen %d, modal %q at %dx%d: %v", scr, modal, size[0], size[1], err)
+					continue
+				}
+				_, tooSmallErr := a.g.View("too-small")
+				if tooSmall := size[0] < minLayoutWidth || size[1] < minLayoutHeight; tooSmall != (tooSmallErr == nil) {
+					t.Errorf("screen %d, modal %q at %dx%d: too-small notice shown = %v", scr, modal, size[0], size[1], tooSmallErr == nil)
+				}
+				if modal == "edit" && size[0] >= minLayoutWidth {
+					x0, y0, x1, y1, err := a.g.ViewPosition("edit")
+					if err != nil {
+						t.Fatalf("edit modal gone at %dx%d", size[0], size[1])
+					}
+					if x0 < 0 || y0 < 0 || x1 >= size[0] || y1 >= size[1] {
+						t.Errorf("edit modal at (%d,%d)-(%d,%d) outside %dx%d", x0, y0, x1, y1, size[0], size[1])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutRecoversFromTooSmall(t *testing.T) {
+	a := layoutTestApp()
+	if err := a.layoutSize(20, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.layoutSize(minLayoutWidth, minLayoutHeight); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.g.View("too-small"); err == nil {
+		t.Error("too-small notice still shown at the minimum size")
+	}
+	if _, err := a.g.View("endpoints"); err != nil {
+		t.Error("endpoint list not laid out")
+	}
+}
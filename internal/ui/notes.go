@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+// maxNoteLines caps the notes panel height in the builder; longer notes
+// scroll off and are read in full in the editor.
+const maxNoteLines = 4
+
+// endpointNote returns the note saved for the active endpoint.
+func (a *App) endpointNote() string {
+	return a.specConfig().Notes[endpointKey(a.activeEndpoint)]
+}
+
+// layoutNotes shows the active endpoint's note below the selected endpoint,
+// if it has one and there's room, and returns the row the param panes start
+// at. room is the number of rows the notes may take.
+func (a *App) layoutNotes(maxX, top, room int) (int, error) {
+	note := a.endpointNote()
+	lines := min(strings.Count(note, "\n")+1, maxNoteLines, room-1)
+	if note == "" || lines < 1 {
+		a.g.DeleteView("notes")
+		return top, nil
+	}
+	v, err := a.g.SetView("notes", 0, top, maxX-1, top+lines+1)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return top, err
+		}
+		v.Title = "Notes (m to edit)"
+		v.Wrap = true
+	}
+	v.Clear()
+	fmt.Fprint(v, colorDim+note+colorReset)
+	return top + lines + 1, nil
+}
+
+// editNotes opens the active endpoint's note in $EDITOR, or in the edit
+// modal with --no-editor; saving it empty deletes it.
+func (a *App) editNotes(g *gocui.Gui, _ *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	key := endpointKey(a.activeEndpoint)
+	if a.noEditor {
+		return a.openEditModal(g, "Notes (empty to delete)", a.endpointNote(), "notes:"+key)
+	}
+	f, err := os.CreateTemp("", "xhark-notes-*.md")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	if _, err := f.WriteString(a.endpointNote()); err != nil {
+		return nil
+	}
+	a.suspendEditorFile = f.Name()
+	a.notesFor = key
+	return gocui.ErrQuit
+}
+
+// saveNote persists the note for an endpoint key.
+func (a *App) saveNote(key, note string) {
+	a.specConfig().SetNote(key, strings.TrimSpace(note))
+	if err := a.cfg.Save(); err != nil {
+		a.errorMsg = "save config: " + err.Error()
+	}
+}
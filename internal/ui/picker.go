@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/jroimartin/gocui"
+)
+
+// picker is a small modal list used to choose one of several named items
+// (e.g. saved presets). It sits on top of the current screen.
+type picker struct {
+	title string
+	items []string
+	sel   int
+
+	onPick func(i int) error
+	// onDelete is optional; when set, ctrl+d removes the selected item.
+	onDelete func(i int) error
+	// canDelete optionally limits which items ctrl+d applies to.
+	canDelete func(i int) bool
+}
+
+func (a *App) openPicker(p *picker) {
+	a.picker = p
+	a.errorMsg = ""
+}
+
+func (a *App) closePicker() {
+	a.picker = nil
+	if a.g != nil {
+		if v, err := a.g.View("picker"); err == nil {
+			v.Clear()
+			a.g.DeleteView("picker")
+		}
+	}
+}
+
+func (a *App) layoutPicker(maxX, maxY int) error {
+	p := a.picker
+	width := 50
+	for _, it := range p.items {
+		width = max(width, utf8.RuneCountInString(stripANSI(it))+2)
+	}
+	if width > maxX-4 {
+		width = maxX - 4
+	}
+	height := len(p.items) + 1
+	if height > maxY-6 {
+		height = maxY - 6
+	}
+	if height < 2 {
+		height = 2
+	}
+	x0 := (maxX - width) / 2
+	y0 := (maxY - height) / 2
+
+	v, err := a.g.SetView("picker", x0, y0, x0+width, y0+height)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Highlight = true
+		v.SelFgColor = gocui.ColorBlack
+		v.SelBgColor = gocui.ColorGreen
+	}
+	v.Title = " " + p.title + " "
+	v.Clear()
+	for _, it := range p.items {
+		fmt.Fprintln(v, it)
+	}
+	if len(p.items) == 0 {
+		fmt.Fprintln(v, "(none)")
+	}
+	v.SetCursor(0, p.sel)
+	if _, err := a.g.SetViewOnTop("picker"); err != nil {
+		return err
+	}
+	if _, err := a.g.SetCurrentView("picker"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *App) movePicker(delta int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		p := a.picker
+		if p == nil || len(p.items) == 0 {
+			return nil
+		}
+		p.sel += delta
+		if p.sel < 0 {
+			p.sel = 0
+		}
+		if p.sel >= len(p.items) {
+			p.sel = len(p.items) - 1
+		}
+		return nil
+	}
+}
+
+func (a *App) confirmPicker(*gocui.Gui, *gocui.View) error {
+	p := a.picker
+	if p == nil || len(p.items) == 0 {
+		return nil
+	}
+	a.closePicker()
+	return p.onPick(p.sel)
+}
+
+func (a *App) deletePickerItem(*gocui.Gui, *gocui.View) error {
+	p := a.picker
+	if p == nil || p.onDelete == nil || len(p.items) == 0 {
+		return nil
+	}
+	if p.canDelete != nil && !p.canDelete(p.sel) {
+		return nil
+	}
+	if err := p.onDelete(p.sel); err != nil {
+		return err
+	}
+	p.items = append(p.items[:p.sel], p.items[p.sel+1:]...)
+	if p.sel >= len(p.items) && p.sel > 0 {
+		p.sel--
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+// openHeaderPreview builds the request and lists every header it will be
+// sent with (defaults, content type, header params and auth) for a last
+// review: each can be edited or deleted, and more added, before sending.
+func (a *App) openHeaderPreview(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenBuilder || a.editing {
+		return nil
+	}
+	if msg := a.requireBaseURL(); msg != "" {
+		a.errorMsg = msg
+		return nil
+	}
+	req, err := a.buildActiveRequest()
+	if err != nil {
+		a.errorMsg = err.Error()
+		return nil
+	}
+	if req.Headers == nil {
+		req.Headers = map[string]string{}
+	}
+	a.preview = &req
+	a.showHeaderPreview()
+	return nil
+}
+
+func (a *App) showHeaderPreview() {
+	req := a.preview
+	if req == nil {
+		return
+	}
+	names := make([]string, 0, len(req.Headers))
+	for k := range req.Headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	items := make([]string, 0, len(names)+2)
+	for _, k := range names {
+		items = append(items, k+": "+req.Headers[k])
+	}
+	items = append(items, "+ add header", colorGreen+"> send "+req.Method+" "+req.URL+colorReset)
+	a.openPicker(&picker{
+		title: "Request headers (enter=edit, ctrl+d=delete)",
+		items: items,
+		sel:   len(items) - 1,
+		onPick: func(i int) error {
+			switch {
+			case i < len(names):
+				return a.openEditModal(a.g, names[i]+" (empty removes it)", req.Headers[names[i]], "sendheader:"+names[i])
+			case i == len(names):
+				return a.openEditModal(a.g, "add header (Name: value)", "", "sendheader:")
+			}
+			a.preview = nil
+			a.send(*req, false)
+			return nil
+		},
+		canDelete: func(i int) bool {
+			return i < len(names)
+		},
+		onDelete: func(i int) error {
+			delete(req.Headers, names[i])
+			names = append(names[:i], names[i+1:]...)
+			return nil
+		},
+	})
+}
+
+// setPreviewHeader applies an edit from the header preview: a new value for
+// name, or with no name a "Name: value" line to add.
+func (a *App) setPreviewHeader(name, val string) {
+	if a.preview == nil {
+		return
+	}
+	if name == "" {
+		k, v, ok := strings.Cut(val, ":")
+		name, val = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || name == "" {
+			a.notify("expected Name: value")
+			return
+		}
+	}
+	for k := range a.preview.Headers {
+		if strings.EqualFold(k, name) {
+			delete(a.preview.Headers, k)
+		}
+	}
+	if val != "" {
+		a.preview.Headers[name] = val
+	}
+}
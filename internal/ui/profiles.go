@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+
+	"xhark/internal/config"
+)
+
+const defaultAuthProfile = "default"
+
+// staleTokenAge is when an OAuth token is flagged as likely expired.
+const staleTokenAge = 12 * time.Hour
+
+// resetAuthProfiles starts the current spec with the default profile, the
+// profile names saved for it in the config, and the tokens kept in the auth
+// store.
+func (a *App) resetAuthProfiles() {
+	a.authProfiles = map[string]map[string]authState{defaultAuthProfile: {}}
+	a.authProfile = defaultAuthProfile
+	var active string
+	if a.cfg != nil {
+		if sc := a.cfg.Specs[a.specURL]; sc != nil {
+			for _, name := range sc.AuthProfiles {
+				a.authProfiles[name] = map[string]authState{}
+			}
+			active = sc.AuthProfile
+		}
+	}
+	if a.authFile != nil {
+		for profile, tokens := range a.authFile.Spec(a.specURL) {
+			store := a.authProfiles[profile]
+			if store == nil {
+				store = map[string]authState{}
+				a.authProfiles[profile] = store
+			}
+			for scheme, t := range tokens {
+				store[scheme] = authState{schemeName: scheme, token: t.Token, tokenType: t.TokenType, acquiredAt: t.AcquiredAt}
+			}
+		}
+	}
+	if a.authProfiles[active] != nil {
+		a.authProfile = active
+	}
+	a.authStore = a.authProfiles[a.authProfile]
+}
+
+// saveAuthTokens writes the current spec's tokens, for every profile, to the
+// auth store (unless XHARK_AUTH_STORE turned it off).
+func (a *App) saveAuthTokens() {
+	if a.authFile == nil {
+		return
+	}
+	profiles := map[string]map[string]config.AuthToken{}
+	for profile, store := range a.authProfiles {
+		tokens := map[string]config.AuthToken{}
+		for scheme, st := range store {
+			tokens[scheme] = config.AuthToken{Token: st.token, TokenType: st.tokenType, AcquiredAt: st.acquiredAt}
+		}
+		profiles[profile] = tokens
+	}
+	a.authFile.SetSpec(a.specURL, profiles)
+	if err := a.authFile.Save(); err != nil {
+		a.errorMsg = "save auth store: " + err.Error()
+	}
+}
+
+func tokenStale(st authState) bool {
+	return !st.acquiredAt.IsZero() && time.Since(st.acquiredAt) > staleTokenAge
+}
+
+// shortAge renders d as its largest whole unit: "5m", "3h", "2d".
+func shortAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+}
+
+// authProfileNames lists the profiles, default first.
+func (a *App) authProfileNames() []string {
+	names := []string{defaultAuthProfile}
+	for name := range a.authProfiles {
+		if name != defaultAuthProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// useAuthProfile makes name (creating it if needed) the profile requests are
+// authorized with.
+func (a *App) useAuthProfile(name string) {
+	if a.authProfiles[name] == nil {
+		a.authProfiles[name] = map[string]authState{}
+	}
+	a.authProfile = name
+	a.authStore = a.authProfiles[name]
+	if len(a.specs) > 0 {
+		a.specs[a.activeSpec].authProfile = name
+	}
+	a.saveAuthProfiles()
+	if a.authOpen {
+		a.loadAuthFormFromStore()
+	}
+}
+
+func (a *App) deleteAuthProfile(name string) {
+	delete(a.authProfiles, name)
+	a.saveAuthTokens()
+	if a.authProfile == name {
+		a.useAuthProfile(defaultAuthProfile)
+		return
+	}
+	a.saveAuthProfiles()
+}
+
+func (a *App) saveAuthProfiles() {
+	sc := a.specConfig()
+	sc.AuthProfiles = a.authProfileNames()[1:]
+	sc.AuthProfile = ""
+	if a.authProfile != defaultAuthProfile {
+		sc.AuthProfile = a.authProfile
+	}
+	if err := a.cfg.Save(); err != nil {
+		a.errorMsg = "save config: " + err.Error()
+	}
+}
+
+// openAuthProfiles lists the auth profiles to switch between, plus an entry
+// to add one.
+func (a *App) openAuthProfiles(*gocui.Gui, *gocui.View) error {
+	if !a.authOpen || a.authEditing || a.picker != nil {
+		return nil
+	}
+	names := a.authProfileNames()
+	items := make([]string, 0, len(names)+1)
+	sel := 0
+	for i, name := range names {
+		mark := "  "
+		if name == a.authProfile {
+			mark = "* "
+			sel = i
+		}
+		items = append(items, mark+name)
+	}
+	items = append(items, "+ new profile")
+	a.openPicker(&picker{
+		title: "Auth profiles (enter=use, ctrl+d=delete)",
+		items: items,
+		sel:   sel,
+		onPick: func(i int) error {
+			if i == len(names) {
+				return a.openEditModal(a.g, "new auth profile", "", "profile:new")
+			}
+			a.useAuthProfile(names[i])
+			a.notify("auth profile: " + names[i])
+			return nil
+		},
+		canDelete: func(i int) bool {
+			return i > 0 && i < len(names)
+		},
+		onDelete: func(i int) error {
+			a.deleteAuthProfile(names[i])
+			names = append(names[:i], names[i+1:]...)
+			return nil
+		},
+	})
+	return nil
+}
+
+// addAuthProfile creates the named profile and switches to it.
+func (a *App) addAuthProfile(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	a.useAuthProfile(name)
+	a.notify("auth profile: " + name)
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"xhark/internal/httpclient"
+)
+
+// logExchange writes a sent request and what came back to the debug log
+// (XHARK_DEBUG=1). Bodies are logged byte-for-byte unless logPretty is set.
+func (a *App) logExchange(req httpclient.RequestSpec, res httpclient.Result, err error) {
+	var b strings.Builder
+	b.WriteString("> " + req.Method + " " + a.redactURL(req.URL) + "\n")
+	writeLogHeaders(&b, "> ", a.redactHeaders(req.Headers))
+	if len(req.Body) > 0 {
+		b.WriteString(a.logBody(req.Headers["Content-Type"], req.Body) + "\n")
+	}
+	if err != nil {
+		b.WriteString("< no response: " + err.Error())
+		debugLog.Print(b.String())
+		return
+	}
+	b.WriteString("< " + res.Status + " (" + res.Elapsed.String() + ")\n")
+	writeLogHeaders(&b, "< ", a.redactHeaders(res.Headers))
+	if len(res.Raw) > 0 {
+		b.WriteString(a.logBody(res.Headers["content-type"], res.Raw))
+	}
+	debugLog.Print(b.String())
+}
+
+func writeLogHeaders(b *strings.Builder, prefix string, h map[string]string) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(prefix + k + ": " + h[k] + "\n")
+	}
+}
+
+// redacted replaces credentials in logs and exports.
+const redacted = "<redacted>"
+
+// alwaysSecretHeaders carry credentials whatever the spec declares.
+var alwaysSecretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// secretParams returns the lowercased header names and the query param names
+// that hold credentials: the fixed ones plus every apiKey scheme's.
+func (a *App) secretParams() (headers, query map[string]bool) {
+	headers, query = map[string]bool{}, map[string]bool{}
+	for _, h := range alwaysSecretHeaders {
+		headers[strings.ToLower(h)] = true
+	}
+	for _, ss := range a.secSchemes {
+		if ss.Type != "apiKey" || ss.ParamName == "" {
+			continue
+		}
+		switch ss.In {
+		case "query":
+			query[ss.ParamName] = true
+		case "header":
+			headers[strings.ToLower(ss.ParamName)] = true
+		}
+	}
+	return headers, query
+}
+
+// redactHeaders returns a copy of h with credential values masked.
+func (a *App) redactHeaders(h map[string]string) map[string]string {
+	secret, _ := a.secretParams()
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if secret[strings.ToLower(k)] {
+			v = redacted
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactURL masks the password in raw's userinfo and the values of apiKey
+// query params, leaving the rest of the query as sent.
+func (a *App) redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	_, secret := a.secretParams()
+	if u.RawQuery != "" && len(secret) > 0 {
+		parts := strings.Split(u.RawQuery, "&")
+		for i, part := range parts {
+			k, _, _ := strings.Cut(part, "=")
+			if name, err := url.QueryUnescape(k); err == nil && secret[name] {
+				parts[i] = k + "=" + url.QueryEscape(redacted)
+			}
+		}
+		u.RawQuery = strings.Join(parts, "&")
+	}
+	return u.Redacted()
+}
+
+// logBody renders a body for the log: raw, or indented by the response
+// colorizer with the colors stripped when logPretty is on.
+func (a *App) logBody(contentType string, body []byte) string {
+	if !a.logPretty {
+		return string(body)
+	}
+	return stripANSI(httpclient.FormatBody(contentType, body, httpclient.FormatOptions{}))
+}
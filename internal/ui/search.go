@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+const (
+	colorMatch        = "\033[30;43m" // black on yellow
+	colorCurrentMatch = "\033[30;42m" // black on green
+)
+
+func (a *App) openResponseSearch(g *gocui.Gui, _ *gocui.View) error {
+	if a.scr != screenResponse || a.editing || a.resErr != nil {
+		return nil
+	}
+	return a.openEditModal(g, "search response", a.resSearch, "search:response")
+}
+
+// setResponseSearch highlights term in the response and scrolls to its
+// first match. An empty term clears the search.
+func (a *App) setResponseSearch(term string) {
+	a.resSearch = term
+	a.resMatch = 0
+	a.renderResponse()
+	if term == "" {
+		return
+	}
+	if len(a.resMatches) == 0 {
+		a.errorMsg = "no match for " + term
+		return
+	}
+	a.errorMsg = ""
+	a.showMatch()
+}
+
+func (a *App) nextMatch(delta int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if a.scr != screenResponse {
+			return nil
+		}
+		if len(a.resMatches) == 0 {
+			if a.resSearch == "" {
+				a.errorMsg = "press / to search the response"
+			}
+			return nil
+		}
+		n := len(a.resMatches)
+		a.resMatch = ((a.resMatch+delta)%n + n) % n
+		a.renderResponse()
+		a.showMatch()
+		return nil
+	}
+}
+
+// showMatch scrolls the current match into view, a couple of lines down
+// for context.
+func (a *App) showMatch() {
+	v, err := a.g.View("response")
+	if err != nil || a.resMatch >= len(a.resMatches) {
+		return
+	}
+	v.SetOrigin(0, max(0, a.resMatches[a.resMatch]-2))
+	a.notify(fmt.Sprintf("match %d/%d", a.resMatch+1, len(a.resMatches)))
+}
+
+// writeResponseBody prints the body, highlighting search matches (case
+// insensitive) and recording the view lines they're on. Matched lines lose
+// their JSON colors so the highlight reads clearly.
+func (a *App) writeResponseBody(v *gocui.View, body string) {
+	if a.resSearch == "" {
+		fmt.Fprintln(v, body)
+		return
+	}
+	offset := max(0, len(v.BufferLines())-1)
+	for i, line := range strings.Split(body, "\n") {
+		hl, ok := highlightMatches(stripANSI(line), a.resSearch, len(a.resMatches) == a.resMatch)
+		if !ok {
+			fmt.Fprintln(v, line)
+			continue
+		}
+		a.resMatches = append(a.resMatches, offset+i)
+		fmt.Fprintln(v, hl)
+	}
+	if a.resMatch >= len(a.resMatches) {
+		a.resMatch = 0
+	}
+}
+
+// highlightMatches wraps every occurrence of term in plain; current marks
+// the line holding the match being looked at.
+func highlightMatches(plain, term string, current bool) (string, bool) {
+	lower, needle := strings.ToLower(plain), strings.ToLower(term)
+	if len(lower) != len(plain) {
+		// lowercasing changed byte offsets; match case-sensitively instead
+		lower, needle = plain, term
+	}
+	if needle == "" || !strings.Contains(lower, needle) {
+		return plain, false
+	}
+	color := colorMatch
+	if current {
+		color = colorCurrentMatch
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 {
+			b.WriteString(plain)
+			break
+		}
+		b.WriteString(plain[:i] + color + plain[i:i+len(needle)] + colorReset)
+		plain, lower = plain[i+len(needle):], lower[i+len(needle):]
+	}
+	return b.String(), true
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jroimartin/gocui"
+
+	"xhark/internal/httpclient"
+)
+
+// inFlight is a request that has been sent and not yet answered.
+type inFlight struct {
+	req     httpclient.RequestSpec
+	started time.Time
+}
+
+// send executes req in the background so the UI stays responsive, ticking
+// the elapsed time in the footer until the response (or error) arrives.
+// rerun keeps the current response around for diffing.
+func (a *App) send(req httpclient.RequestSpec, rerun bool) {
+	if a.inFlight != nil {
+		a.errorMsg = "a request is already in flight"
+		return
+	}
+	a.inFlight = &inFlight{req: req, started: time.Now()}
+	a.errorMsg = ""
+	entry := a.newHistoryEntry(req)
+	done := make(chan struct{})
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout(req.Method))
+		defer cancel()
+		res, err := httpclient.Execute(ctx, &a.client, req)
+		close(done)
+		a.g.Update(func(*gocui.Gui) error {
+			a.inFlight = nil
+			a.finishRequest(entry, res, err, rerun)
+			return nil
+		})
+	}()
+	go a.tickUntil(done)
+}
+
+// tickUntil redraws the UI every 100ms until done is closed, so elapsed
+// times shown while waiting keep counting.
+func (a *App) tickUntil(done <-chan struct{}) {
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			a.g.Update(func(*gocui.Gui) error { return nil })
+		}
+	}
+}
+
+// fetchToken runs the OAuth2 password flow for scheme name in the background,
+// showing its progress in the auth modal, and stores the token it gets.
+func (a *App) fetchToken(name, tokenURL string) {
+	if !a.tokenFetchStarted.IsZero() {
+		return
+	}
+	a.tokenFetchStarted = time.Now()
+	a.authError = ""
+	a.renderAuth()
+	store := a.authStore
+	baseURL, user, pass, scope := a.baseURL, a.authUsername, a.authPassword, a.authScope
+	// the token request is a POST, so --timeout-post covers it too
+	timeout := a.requestTimeout(http.MethodPost)
+	done := make(chan struct{})
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		accessToken, tokenType, err := httpclient.FetchOAuthPasswordToken(ctx, &a.client, baseURL, tokenURL, user, pass, scope)
+		close(done)
+		a.g.Update(func(*gocui.Gui) error {
+			a.tokenFetchStarted = time.Time{}
+			if err != nil {
+				a.authError = err.Error()
+				a.renderAuth()
+				return nil
+			}
+			if tokenType == "" {
+				tokenType = "Bearer"
+			}
+			store[name] = authState{schemeName: name, tokenType: tokenType, token: accessToken, acquiredAt: time.Now()}
+			a.saveAuthTokens()
+			a.authEditing = false
+			a.notify("token acquired for " + name)
+			a.renderAuth()
+			return nil
+		})
+	}()
+	go a.tickUntil(done)
+}
+
+// finishRequest shows what came back for the request in entry and records
+// it in the history, failed or not.
+func (a *App) finishRequest(entry historyEntry, res httpclient.Result, err error, rerun bool) {
+	req := entry.Request
+	a.logExchange(req, res, err)
+	a.recordHistory(entry, res, err)
+	a.lastReq = req
+	a.resCached = false
+	if rerun && err == nil && a.resErr == nil {
+		prev := a.lastRes
+		a.prevRes = &prev
+	}
+	if !rerun {
+		a.prevRes = nil
+	}
+	if err != nil {
+		a.resErr = err
+	} else {
+		a.setResponse(res)
+	}
+	if err != nil || !rerun {
+		a.showDiff = false
+	}
+	a.scr = screenResponse
+	a.renderResponse()
+}
+
+// inFlightStatus is the footer line while a request is outstanding.
+func (a *App) inFlightStatus() string {
+	f := a.inFlight
+	elapsed := time.Since(f.started).Truncate(100 * time.Millisecond)
+	return fmt.Sprintf("%s%s %s ... %s%s", colorYellow, f.req.Method, f.req.URL, elapsed, colorReset)
+}
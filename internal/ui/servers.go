@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jroimartin/gocui"
+)
+
+// serverCheckTimeout bounds each --check-server probe.
+const serverCheckTimeout = 5 * time.Second
+
+// server is one entry of the spec's servers list. vars are its templated
+// variables in the order they appear in the URL.
+type server struct {
+	url         string
+	description string
+	vars        []serverVar
+}
+
+type serverVar struct {
+	name string
+	def  string
+	enum []string
+}
+
+// serverChoice is a server being picked: the variable values entered so far
+// and the index of the next one to ask for.
+type serverChoice struct {
+	srv  server
+	vals map[string]string
+	next int
+}
+
+func extractServers(doc *openapi3.T) []server {
+	if doc == nil {
+		return nil
+	}
+	var out []server
+	for _, s := range doc.Servers {
+		if s == nil || strings.TrimSpace(s.URL) == "" {
+			continue
+		}
+		srv := server{url: strings.TrimSpace(s.URL), description: strings.TrimSpace(s.Description)}
+		seen := map[string]bool{}
+		for _, m := range pathParamRe.FindAllStringSubmatch(srv.url, -1) {
+			name := m[1]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			sv := serverVar{name: name}
+			if v := s.Variables[name]; v != nil {
+				sv.def = v.Default
+				sv.enum = append([]string(nil), v.Enum...)
+			}
+			srv.vars = append(srv.vars, sv)
+		}
+		out = append(out, srv)
+	}
+	return out
+}
+
+// expand substitutes vals into the server URL, falling back to each
+// variable's default. Variables with neither stay braced.
+func (s server) expand(vals map[string]string) string {
+	u := s.url
+	for _, v := range s.vars {
+		val := firstNonEmpty(strings.TrimSpace(vals[v.name]), v.def)
+		if val != "" {
+			u = strings.ReplaceAll(u, "{"+v.name+"}", val)
+		}
+	}
+	return u
+}
+
+// openServerPicker lists the spec's servers; picking one asks for its
+// variables, then makes it the base URL.
+func (a *App) openServerPicker(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints || a.editing {
+		return nil
+	}
+	if a.mergeSpecs && len(a.extraSpecs) > 0 {
+		a.errorMsg = "server selection isn't available with --merge-specs"
+		return nil
+	}
+	if len(a.servers) == 0 {
+		a.errorMsg = "the spec declares no servers"
+		return nil
+	}
+	items := make([]string, len(a.servers))
+	for i, s := range a.servers {
+		items[i] = highlightPathParams(s.url)
+		if s.description != "" {
+			items[i] += "  " + colorDim + s.description + colorReset
+		}
+	}
+	a.openPicker(&picker{
+		title: "Servers",
+		items: items,
+		onPick: func(i int) error {
+			a.serverChoice = &serverChoice{srv: a.servers[i], vals: map[string]string{}}
+			return a.promptServerVar()
+		},
+	})
+	return nil
+}
+
+// promptServerVar asks for the next variable of the server being picked:
+// from a list when it's an enum, otherwise in the edit modal with its
+// default filled in. Once all are set the server is applied.
+func (a *App) promptServerVar() error {
+	c := a.serverChoice
+	if c == nil {
+		return nil
+	}
+	if c.next >= len(c.srv.vars) {
+		a.serverChoice = nil
+		a.useServer(c.srv.expand(c.vals))
+		return nil
+	}
+	sv := c.srv.vars[c.next]
+	if len(sv.enum) == 0 {
+		return a.openEditModal(a.g, "server variable "+sv.name, sv.def, "server:"+sv.name)
+	}
+	enum := sv.enum
+	sel := 0
+	for i, e := range enum {
+		if e == sv.def {
+			sel = i
+		}
+	}
+	a.openPicker(&picker{
+		title: sv.name,
+		items: enum,
+		sel:   sel,
+		onPick: func(i int) error {
+			return a.setServerVar(sv.name, enum[i])
+		},
+	})
+	return nil
+}
+
+func (a *App) setServerVar(name, val string) error {
+	c := a.serverChoice
+	if c == nil {
+		return nil
+	}
+	c.vals[name] = val
+	c.next++
+	return a.promptServerVar()
+}
+
+// useServer makes u (a server URL with its variables filled in) the base URL
+// of the active spec.
+func (a *App) useServer(u string) {
+	base := resolveServerURL(u, a.specURL)
+	if base == "" {
+		a.errorMsg = fmt.Sprintf("can't use server %s (unset variables, or relative to a local spec file; set --base-url)", u)
+		return
+	}
+	a.baseURL = base
+	if len(a.specs) > 0 {
+		a.specs[a.activeSpec].baseURL = base
+	}
+	a.notify("base URL: " + base)
+}
+
+// serverBases lists the distinct base URLs of every loaded spec, for
+// checkServers. It reads the specs, so it runs on the UI goroutine.
+func (a *App) serverBases() []string {
+	seen := map[string]bool{}
+	var bases []string
+	for _, s := range a.specs {
+		for _, ep := range s.endpoints {
+			if ep.BaseURL != "" && !seen[ep.BaseURL] {
+				seen[ep.BaseURL] = true
+				bases = append(bases, ep.BaseURL)
+			}
+		}
+		if s.baseURL != "" && !seen[s.baseURL] {
+			seen[s.baseURL] = true
+			bases = append(bases, s.baseURL)
+		}
+	}
+	return bases
+}
+
+// checkServers probes bases and, if any can't be reached, shows which in the
+// footer. Any HTTP answer, even an error status, counts as reachable; only
+// connection failures are reported.
+func (a *App) checkServers(g *gocui.Gui, bases []string) {
+	client := &http.Client{
+		Timeout:   serverCheckTimeout,
+		Transport: a.client.Transport(),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	var down []string
+	for _, base := range bases {
+		if err := probeServer(client, base); err != nil {
+			shown := base
+			if u, perr := url.Parse(base); perr == nil {
+				shown = u.Redacted()
+			}
+			debugLog.Printf("check server %s: %v", shown, err)
+			down = append(down, shown)
+		}
+	}
+	if len(down) == 0 {
+		return
+	}
+	g.Update(func(*gocui.Gui) error {
+		a.errorMsg = fmt.Sprintf("can't reach %s; if the spec's servers don't match your environment, pass --base-url", strings.Join(down, ", "))
+		return nil
+	})
+}
+
+func probeServer(client *http.Client, base string) error {
+	req, err := http.NewRequest(http.MethodHead, base, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
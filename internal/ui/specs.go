@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+
+	"xhark/internal/model"
+)
+
+// loadedSpec is everything that belongs to one API when several specs are
+// loaded: its endpoints, where requests go, and the tokens acquired for it.
+type loadedSpec struct {
+	specURL    string
+	title      string
+	baseURL    string
+	endpoints  []model.Endpoint
+	secSchemes map[string]model.SecurityScheme
+	servers    []server
+
+	authProfiles map[string]map[string]authState
+	authProfile  string
+}
+
+// AddSpec queues another OpenAPI spec to load next to the one given to
+// SetSpec; the spec switcher (ctrl+o) moves between them.
+func (a *App) AddSpec(spec string) {
+	a.extraSpecs = append(a.extraSpecs, spec)
+}
+
+// loadSpecs loads the primary spec and any extra ones. --base-url and
+// --spec-override apply to the primary spec only.
+func (a *App) loadSpecs() error {
+	if err := a.loadEndpoints(); err != nil {
+		return err
+	}
+	a.resetAuthProfiles()
+	a.specs = []*loadedSpec{a.currentSpec()}
+	for _, spec := range a.extraSpecs {
+		a.specURL, a.specOverride, a.postmanFile = spec, "", ""
+		a.baseURL, a.specTitle = "", ""
+		if err := a.loadEndpoints(); err != nil {
+			return fmt.Errorf("%s: %w", spec, err)
+		}
+		a.resetAuthProfiles()
+		a.specs = append(a.specs, a.currentSpec())
+	}
+	if a.mergeSpecs && len(a.specs) > 1 {
+		merged := mergeLoadedSpecs(a.specs)
+		a.specURL = merged.specURL
+		a.resetAuthProfiles()
+		merged.authProfiles, merged.authProfile = a.authProfiles, a.authProfile
+		a.specs = []*loadedSpec{merged}
+	}
+	a.activateSpec(0)
+	return nil
+}
+
+// mergeLoadedSpecs combines specs into one endpoint list. Each endpoint is
+// tagged with its service (a slug of the spec title) and keeps its spec's
+// base URL; security scheme names are namespaced the same way so two
+// services' "bearer" schemes hold separate tokens.
+func mergeLoadedSpecs(specs []*loadedSpec) *loadedSpec {
+	merged := &loadedSpec{
+		specURL:    "merged:",
+		title:      "merged",
+		baseURL:    specs[0].baseURL,
+		secSchemes: map[string]model.SecurityScheme{},
+	}
+	seen := map[string]int{}
+	var keys []string
+	for _, s := range specs {
+		svc := fileSlug(firstNonEmpty(s.title, s.specURL))
+		if seen[svc]++; seen[svc] > 1 {
+			svc = fmt.Sprintf("%s-%d", svc, seen[svc])
+		}
+		keys = append(keys, s.specURL)
+
+		for name, ss := range s.secSchemes {
+			ss.Name = svc + "/" + name
+			// A relative token URL resolves against the service, not
+			// whichever base URL happens to be active.
+			if ss.TokenURL != "" && s.baseURL != "" {
+				if base, err := url.Parse(strings.TrimRight(s.baseURL, "/") + "/"); err == nil {
+					if ref, err := url.Parse(ss.TokenURL); err == nil {
+						ss.TokenURL = base.ResolveReference(ref).String()
+					}
+				}
+			}
+			merged.secSchemes[ss.Name] = ss
+		}
+		for _, ep := range s.endpoints {
+			ep.Service = svc
+			ep.BaseURL = s.baseURL
+			ep.Tags = append([]string{svc}, ep.Tags...)
+			var sec []model.SecurityRequirement
+			for _, req := range ep.Security {
+				r := model.SecurityRequirement{}
+				for name, scopes := range req {
+					r[svc+"/"+name] = scopes
+				}
+				sec = append(sec, r)
+			}
+			ep.Security = sec
+			merged.endpoints = append(merged.endpoints, ep)
+		}
+	}
+	// also keys per-spec config (pins, presets) for this combination
+	merged.specURL += strings.Join(keys, ",")
+	return merged
+}
+
+func (a *App) currentSpec() *loadedSpec {
+	return &loadedSpec{
+		specURL:    a.specURL,
+		title:      a.specTitle,
+		baseURL:    a.baseURL,
+		endpoints:  a.endpoints,
+		secSchemes: a.secSchemes,
+		servers:    a.servers,
+
+		authProfiles: a.authProfiles,
+		authProfile:  a.authProfile,
+	}
+}
+
+// activateSpec makes specs[i] the active API and returns to its endpoint list.
+// Auth profiles are shared by reference, so tokens stay with their spec.
+func (a *App) activateSpec(i int) {
+	s := a.specs[i]
+	a.activeSpec = i
+	a.specURL = s.specURL
+	a.specTitle = s.title
+	a.baseURL = s.baseURL
+	a.endpoints = s.endpoints
+	a.secSchemes = s.secSchemes
+	a.servers = s.servers
+	a.authProfiles = s.authProfiles
+	a.authProfile = s.authProfile
+	a.authStore = s.authProfiles[s.authProfile]
+	a.activeEndpoint = model.Endpoint{}
+	a.prevRes = nil
+	a.showDiff = false
+	a.scr = screenEndpoints
+	a.buildFacets()
+	a.filter = ""
+	a.selected = 0
+	a.recomputeFilter()
+}
+
+// openSpecSwitcher lists the loaded specs to switch between.
+func (a *App) openSpecSwitcher(*gocui.Gui, *gocui.View) error {
+	if a.scr != screenEndpoints || a.editing {
+		return nil
+	}
+	if len(a.specs) < 2 {
+		a.errorMsg = "only one spec loaded (repeat --spec-url/--spec-file to load more)"
+		return nil
+	}
+	items := make([]string, len(a.specs))
+	for i, s := range a.specs {
+		mark := "  "
+		if i == a.activeSpec {
+			mark = "* "
+		}
+		items[i] = mark + firstNonEmpty(s.title, s.specURL)
+	}
+	a.openPicker(&picker{
+		title: "Switch spec",
+		items: items,
+		sel:   a.activeSpec,
+		onPick: func(i int) error {
+			a.activateSpec(i)
+			a.notify("switched to " + firstNonEmpty(a.specTitle, a.specURL))
+			return nil
+		},
+	})
+	return nil
+}
+
+// reloadSpec loads the active spec again in the background, e.g. after the
+// backend restarted with new routes, keeping the filter, facets and selected
+// endpoint.
+func (a *App) reloadSpec(*gocui.Gui, *gocui.View) error {
+	if a.editing || a.reloading {
+		return nil
+	}
+	if a.mergeSpecs && len(a.specs) > 1 {
+		a.errorMsg = "reload isn't supported with --merge-specs; restart xhark"
+		return nil
+	}
+	a.reloading = true
+	a.errorMsg = ""
+	opts, specURL, override, postmanFile := a.specOptions(), a.specURL, a.specOverride, a.postmanFile
+	active := a.activeSpec
+	go func() {
+		apply, err := fetchEndpoints(opts, specURL, override, postmanFile)
+		a.g.Update(func(*gocui.Gui) error {
+			a.reloading = false
+			if err != nil {
+				a.errorMsg = "reload: " + err.Error()
+				return nil
+			}
+			if a.activeSpec != active || a.specURL != specURL {
+				a.errorMsg = "reload dropped: switched spec meanwhile"
+				return nil
+			}
+			a.applyReload(apply)
+			return nil
+		})
+	}()
+	return nil
+}
+
+// applyReload swaps in a reloaded spec, keeping the list state around it.
+func (a *App) applyReload(apply func(*App)) {
+	filter := a.filter
+	facets := a.activeFacets
+	var selKey string
+	if idx, ok := a.selectedEndpoint(); ok {
+		selKey = endpointKey(a.endpoints[idx])
+	}
+	apply(a)
+	if len(a.specs) > 0 {
+		a.specs[a.activeSpec] = a.currentSpec()
+	}
+	for _, f := range a.facets {
+		if facets[f] {
+			a.activeFacets[f] = true
+		}
+	}
+	a.filter = filter
+	a.recomputeFilter()
+	for i, ep := range a.endpoints {
+		if endpointKey(ep) == selKey {
+			a.selectEndpointIdx(i)
+			break
+		}
+	}
+	a.notify(fmt.Sprintf("reloaded %d endpoints", len(a.endpoints)))
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"xhark/internal/model"
+	"xhark/internal/openapi"
+)
+
+func TestApplyReloadKeepsListState(t *testing.T) {
+	a := &App{endpoints: []model.Endpoint{
+		{Method: "GET", Path: "/pets"},
+		{Method: "GET", Path: "/pets/{id}"},
+		{Method: "GET", Path: "/users"},
+	}}
+	a.buildFacets()
+	a.filter = "pets"
+	a.recomputeFilter()
+	a.selectEndpointIdx(1)
+
+	a.applyReload(func(a *App) {
+		a.endpoints = []model.Endpoint{
+			{Method: "GET", Path: "/health"},
+			{Method: "GET", Path: "/pets"},
+			{Method: "GET", Path: "/pets/{id}"},
+			{Method: "GET", Path: "/pets/{id}/toys"},
+		}
+		a.buildFacets()
+		a.filter = ""
+		a.selected = 0
+		a.recomputeFilter()
+	})
+
+	if a.filter != "pets" {
+		t.Errorf("filter = %q, want it kept", a.filter)
+	}
+	idx, ok := a.selectedEndpoint()
+	if !ok || a.endpoints[idx].Path != "/pets/{id}" {
+		t.Errorf("selected %v, want /pets/{id} still selected", a.endpoints[idx].Path)
+	}
+	if a.toast != "reloaded 4 endpoints" {
+		t.Errorf("toast = %q", a.toast)
+	}
+}
+
+func TestDocsURLFallsBackToOriginRoot(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"openapi": "3.0.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {"responses": {"200": {"description": "ok"}}}}}}`))
+	}))
+	defer srv.Close()
+
+	apply, err := fetchEndpoints(openapi.Options{}, srv.URL+"/api/docs", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{}
+	apply(a)
+	if a.specURL != srv.URL+"/openapi.json" || len(a.endpoints) != 1 {
+		t.Errorf("spec %s with %d endpoints, want the root openapi.json", a.specURL, len(a.endpoints))
+	}
+}
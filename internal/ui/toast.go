@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/jroimartin/gocui"
+)
+
+// toastDuration is how long a toast stays in the footer.
+const toastDuration = 3 * time.Second
+
+// notify shows a transient message in the footer. Unlike errorMsg it clears
+// itself after toastDuration, and whatever errorMsg holds shows again.
+// A newer toast replaces an older one; the older one's timer is then a no-op.
+func (a *App) notify(msg string) {
+	a.toastSeq++
+	seq := a.toastSeq
+	a.toast = msg
+	if a.g == nil {
+		return
+	}
+	time.AfterFunc(toastDuration, func() {
+		a.g.Update(func(*gocui.Gui) error {
+			if a.toastSeq == seq {
+				a.toast = ""
+			}
+			return nil
+		})
+	})
+}